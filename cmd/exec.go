@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"github.com/ansel1/merry/v2"
+	"github.com/outblocks/outblocks-cli/pkg/actions"
+	"github.com/spf13/cobra"
+)
+
+func (e *Executor) newExecCmd() *cobra.Command {
+	opts := &actions.RunOptions{}
+	flags := &runFlags{opts: opts}
+	execOpts := &actions.RunExecOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "exec <app> -- <command...>",
+		Short: "Execute command in app environment",
+		Long: `Executes one-off command (e.g. migrations, seeds or REPL) in app dir with the same environment that app receives when run locally.
+Dependency vars are resolved only when dependencies needed by app are started with --with-deps.`,
+		Example: `ok exec service.api -- npm run migrate
+ok exec service.api --with-deps -- python manage.py shell`,
+		Annotations: map[string]string{
+			cmdGroupAnnotation:           cmdGroupMain,
+			cmdProjectLoadModeAnnotation: cmdLoadModeFull,
+			cmdAppsLoadModeAnnotation:    cmdLoadModeFull,
+			cmdSecretsLoadAnnotation:     "1",
+		},
+		Args:         cobra.MinimumNArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if dash := cmd.ArgsLenAtDash(); dash > 1 {
+				return merry.New("only one app can be specified before --")
+			}
+
+			err := flags.prepare(cmd.Context(), e, nil)
+			if err != nil {
+				return err
+			}
+
+			execOpts.App = args[0]
+			execOpts.Command = args[1:]
+
+			return actions.NewRun(e.log, e.cfg, opts).Exec(cmd.Context(), execOpts)
+		},
+	}
+
+	f := cmd.Flags()
+	flags.register(f)
+	f.BoolVar(&execOpts.WithDeps, "with-deps", false, "start dependencies needed by app for the time of execution")
+
+	return cmd
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/outblocks/outblocks-cli/pkg/getter"
 	"github.com/outblocks/outblocks-cli/pkg/logger"
 	"github.com/outblocks/outblocks-cli/pkg/plugins"
+	"github.com/outblocks/outblocks-cli/pkg/plugins/client"
 	"github.com/outblocks/outblocks-cli/pkg/server"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
@@ -33,16 +34,22 @@ type Executor struct {
 	loader  *plugins.Loader
 	log     logger.Logger
 
-	srv *server.Server
+	srv       *server.Server
+	traceFile *os.File
 
 	cfg                 *config.Project
+	envCfgs             map[string]*config.Project
 	secrets             map[string]interface{}
 	lastUpdateCheckFile string
 	loadAppsOpts        *config.LoadAppsOptions
 
 	opts struct {
-		env       string
-		valueOpts *values.Options
+		env         string
+		answersFile string
+		traceFile   string
+		frozen      bool
+		pluginDev   map[string]string
+		valueOpts   *values.Options
 	}
 }
 
@@ -69,6 +76,7 @@ func setupEnvVars(env *cli.Environment) {
 	env.AddVarWithDefault("plugins_cache_dir", "plugins cache directory", clipath.DataDir("plugin-cache"))
 	env.AddVar("no_color", "disable color output")
 	env.AddVarWithDefault("log_level", "set logging level (options: debug, notice, info, warn, error)", "info")
+	env.AddVar("plugin_mirror", "local plugin mirror dir or file:// URL to load plugins from before downloading them")
 }
 
 func (e *Executor) commandPreRun(ctx context.Context) error { //nolint:gocyclo
@@ -88,6 +96,24 @@ func (e *Executor) commandPreRun(ctx context.Context) error { //nolint:gocyclo
 		return err
 	}
 
+	if e.opts.answersFile != "" {
+		answers, err := server.LoadAnswers(e.opts.answersFile)
+		if err != nil {
+			return err
+		}
+
+		e.srv.SetAnswers(answers)
+	}
+
+	if e.opts.traceFile != "" {
+		e.traceFile, err = os.Create(e.opts.traceFile)
+		if err != nil {
+			return merry.Errorf("cannot create trace file: %w", err)
+		}
+
+		client.SetTracer(client.NewTracer(logger.RedactWriter(e.traceFile)))
+	}
+
 	helpFlag := e.rootCmd.PersistentFlags().Lookup("help")
 	isHelp := helpFlag.Changed || (len(os.Args) > 1 && strings.EqualFold(os.Args[1], "help"))
 
@@ -104,11 +130,7 @@ func (e *Executor) commandPreRun(ctx context.Context) error { //nolint:gocyclo
 
 	cfgPath := fileutil.FindYAMLGoingUp(pwd, config.ProjectYAMLName)
 
-	for i, v := range e.opts.valueOpts.ValueFiles {
-		e.opts.valueOpts.ValueFiles[i] = strings.ReplaceAll(v, "<env>", e.opts.env)
-	}
-
-	v, valuesLoadErr := e.opts.valueOpts.MergeValues(ctx, filepath.Dir(cfgPath), getter.All())
+	v, valuesLoadErr := e.envValueOptions(e.opts.env).MergeValues(ctx, filepath.Dir(cfgPath), getter.All())
 
 	vals := map[string]interface{}{
 		"var":     v,
@@ -117,7 +139,7 @@ func (e *Executor) commandPreRun(ctx context.Context) error { //nolint:gocyclo
 	}
 
 	// Load essential config file first.
-	configPreloadErr := e.loadProject(ctx, cfgPath, e.srv.Addr().String(), vals, LoadProjectOptions{
+	configPreloadErr := e.loadProject(ctx, cfgPath, e.pluginHost(), vals, LoadProjectOptions{
 		Mode: config.LoadModeEssential,
 	}, config.LoadModeSkip, nil)
 
@@ -166,29 +188,49 @@ func (e *Executor) commandPreRun(ctx context.Context) error { //nolint:gocyclo
 	}
 
 	// Load secrets if needed.
-	if cmd.Annotations[cmdSecretsLoadAnnotation] == "1" && e.cfg.Secrets.Plugin() != nil {
-		e.log.Debugf("Loading secrets from plugin: %s\n", e.cfg.Secrets.Plugin().Name)
+	if cmd.Annotations[cmdSecretsLoadAnnotation] == "1" && e.cfg.Secrets.Provider() != nil {
+		e.log.Debugf("Loading secrets from %s\n", e.cfg.Secrets.Provider().Name())
 
-		secrets, err := e.cfg.Secrets.Plugin().Client().GetSecrets(ctx, e.cfg.Secrets.Type, e.cfg.Secrets.Other)
+		secrets, err := e.cfg.Secrets.Provider().GetSecrets(ctx)
 		if err != nil {
 			return err
 		}
 
 		for k, v := range secrets {
 			e.secrets[k] = v
+
+			logger.AddRedactedValues(v)
 		}
 	}
 
 	// Load config file properly now.
 	loadProjectOptions.SkipLoadPlugins = true
 
-	if err := e.loadProject(ctx, cfgPath, e.srv.Addr().String(), vals, loadProjectOptions, loadAppsMode, loadAppsOpts); err != nil {
+	if err := e.loadProject(ctx, cfgPath, e.pluginHost(), vals, loadProjectOptions, loadAppsMode, loadAppsOpts); err != nil {
 		return err
 	}
 
+	if e.cfg.Secrets != nil {
+		e.srv.SetSecretsFilter(e.cfg.Secrets.IsAllowed)
+	}
+
 	return nil
 }
 
+// envValueOptions returns value options with value files resolved for specified environment.
+func (e *Executor) envValueOptions(env string) *values.Options {
+	opts := &values.Options{
+		Values:     e.opts.valueOpts.Values,
+		ValueFiles: make([]string, len(e.opts.valueOpts.ValueFiles)),
+	}
+
+	for i, v := range e.opts.valueOpts.ValueFiles {
+		opts.ValueFiles[i] = strings.ReplaceAll(v, "<env>", env)
+	}
+
+	return opts
+}
+
 func buildAppsLoadOptions(cmd *cobra.Command, cmdArgs []string) (*config.LoadAppsOptions, error) {
 	if cmd == nil {
 		return nil, nil
@@ -204,6 +246,11 @@ func buildAppsLoadOptions(cmd *cobra.Command, cmdArgs []string) (*config.LoadApp
 		return nil, err
 	}
 
+	// Apps not run locally are routed to remote env by their configured url, so all of them need to be loaded.
+	if f := cmd.Flags().Lookup("remote-env"); f != nil && f.Value.String() != "" {
+		return nil, nil
+	}
+
 	var targets, skips []string
 
 	if cmd.Flags().Lookup("target-apps") != nil {
@@ -242,10 +289,14 @@ func buildAppsLoadOptions(cmd *cobra.Command, cmdArgs []string) (*config.LoadApp
 		skips = append(skips, vals...)
 	}
 
-	if len(cmd.Flags().Args()) > 0 {
-		targets = append(targets, cmd.Flags().Args()...)
+	// Args after -- are not targets, e.g. command run by exec.
+	args := cmd.Flags().Args()
+	if dash := cmd.Flags().ArgsLenAtDash(); dash >= 0 {
+		args = args[:dash]
 	}
 
+	targets = append(targets, args...)
+
 	if len(targets) == 0 {
 		return nil, nil
 	}
@@ -361,6 +412,10 @@ func (e *Executor) startHostServer() error {
 	return e.srv.Serve()
 }
 
+func (e *Executor) pluginHost() plugins.Host {
+	return e.srv
+}
+
 func (e *Executor) Execute(ctx context.Context) error {
 	if err := e.initConfig(); err != nil {
 		return err
@@ -440,6 +495,22 @@ func (e *Executor) Log() logger.Logger {
 	return e.log
 }
 
+// newLoader creates plugin loader for project dir honoring plugin mirror and frozen mode settings.
+func (e *Executor) newLoader(baseDir string) (*plugins.Loader, error) {
+	loader := plugins.NewLoader(baseDir, e.PluginsCacheDir()).WithFrozen(e.opts.frozen)
+
+	if mirror := e.v.GetString("plugin_mirror"); mirror != "" {
+		dir, err := plugins.MirrorDir(mirror)
+		if err != nil {
+			return nil, err
+		}
+
+		loader.WithMirrors(dir)
+	}
+
+	return loader, nil
+}
+
 func (e *Executor) PluginsCacheDir() string {
 	return e.v.GetString("plugins_cache_dir")
 }
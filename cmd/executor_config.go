@@ -3,12 +3,15 @@ package cmd
 import (
 	"context"
 	"path/filepath"
+	"strings"
 
 	"github.com/23doors/go-yaml"
 	"github.com/ansel1/merry/v2"
 	"github.com/outblocks/outblocks-cli/internal/fileutil"
 	"github.com/outblocks/outblocks-cli/pkg/config"
+	"github.com/outblocks/outblocks-cli/pkg/getter"
 	"github.com/outblocks/outblocks-cli/pkg/plugins"
+	"github.com/outblocks/outblocks-cli/pkg/plugins/client"
 )
 
 type LoadProjectOptions struct {
@@ -17,7 +20,7 @@ type LoadProjectOptions struct {
 	SkipCheck       bool
 }
 
-func (e *Executor) loadProject(ctx context.Context, cfgPath, hostAddr string, vals map[string]interface{}, loadProjectOpts LoadProjectOptions, loadAppsMode config.LoadMode, loadAppsOpts *config.LoadAppsOptions) error {
+func (e *Executor) loadProject(ctx context.Context, cfgPath string, host plugins.Host, vals map[string]interface{}, loadProjectOpts LoadProjectOptions, loadAppsMode config.LoadMode, loadAppsOpts *config.LoadAppsOptions) error {
 	cfg, err := config.LoadProjectConfig(cfgPath, vals, loadProjectOpts.Mode, &config.ProjectOptions{
 		Env: e.opts.env,
 	})
@@ -39,13 +42,21 @@ func (e *Executor) loadProject(ctx context.Context, cfgPath, hostAddr string, va
 		loadedPlugins = e.cfg.LoadedPlugins()
 	}
 
-	e.loader = plugins.NewLoader(cfg.Dir, e.PluginsCacheDir())
+	if err := e.applyPluginDevOverrides(cfg); err != nil {
+		return err
+	}
+
+	e.loader, err = e.newLoader(cfg.Dir)
+	if err != nil {
+		return err
+	}
+
 	e.cfg = cfg
 
 	if loadProjectOpts.SkipLoadPlugins {
 		cfg.SetLoadedPlugins(loadedPlugins)
 	} else {
-		if err := cfg.LoadPlugins(ctx, e.log, e.loader, hostAddr); err != nil {
+		if err := cfg.LoadPlugins(ctx, e.log, e.loader, host); err != nil {
 			return err
 		}
 	}
@@ -59,9 +70,102 @@ func (e *Executor) loadProject(ctx context.Context, cfgPath, hostAddr string, va
 	return nil
 }
 
+// loadEnvProject loads essential project config for a different environment, e.g. to access its state or secrets.
+// Only plugins supporting specified actions are started, they are stopped during cleanup.
+func (e *Executor) loadEnvProject(ctx context.Context, env string, actions ...plugins.Action) (*config.Project, error) {
+	if e.cfg == nil {
+		return nil, config.ErrProjectConfigNotFound
+	}
+
+	if env == e.opts.env {
+		return e.cfg, nil
+	}
+
+	if cfg, ok := e.envCfgs[env]; ok {
+		return cfg, nil
+	}
+
+	v, err := e.envValueOptions(env).MergeValues(ctx, e.cfg.Dir, getter.All())
+	if err != nil {
+		return nil, err
+	}
+
+	vals := map[string]interface{}{
+		"var":     v,
+		"env":     env,
+		"secrets": make(map[string]interface{}),
+	}
+
+	cfg, err := config.LoadProjectConfig(e.cfg.YAMLPath(), vals, config.LoadModeEssential, &config.ProjectOptions{
+		Env: env,
+	})
+	if err != nil {
+		return nil, merry.Errorf("cannot load project config for env '%s': %w", env, err)
+	}
+
+	if err := cfg.Normalize(); err != nil {
+		return nil, err
+	}
+
+	if e.envCfgs == nil {
+		e.envCfgs = make(map[string]*config.Project)
+	}
+
+	e.envCfgs[env] = cfg
+
+	if err := e.applyPluginDevOverrides(cfg); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.LoadPluginsWithActions(ctx, e.log, e.loader, e.pluginHost(), actions...); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.EssentialCheck(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// applyPluginDevOverrides replaces sources of plugins specified with --plugin-dev with their local working dirs.
+func (e *Executor) applyPluginDevOverrides(cfg *config.Project) error {
+	for name, dir := range e.opts.pluginDev {
+		var found bool
+
+		for _, plug := range cfg.Plugins {
+			if !strings.EqualFold(plug.Name, name) {
+				continue
+			}
+
+			abs, err := filepath.Abs(dir)
+			if err != nil {
+				return merry.Errorf("cannot resolve dev plugin '%s' dir: %w", name, err)
+			}
+
+			plug.Source = plugins.DevSourcePrefix + filepath.ToSlash(abs)
+			found = true
+		}
+
+		if !found {
+			return merry.Errorf("plugin '%s' specified with --plugin-dev is not defined in project config", name)
+		}
+	}
+
+	return nil
+}
+
 func (e *Executor) cleanupProject() error {
 	e.log.Debugln("Cleaning up.")
 
+	for env, cfg := range e.envCfgs {
+		for _, plug := range cfg.LoadedPlugins() {
+			if err := plug.Stop(); err != nil {
+				return merry.Errorf("error stopping plugin '%s' (env: %s): %w", plug.Name, env, err)
+			}
+		}
+	}
+
 	if e.cfg != nil {
 		for _, plug := range e.cfg.LoadedPlugins() {
 			if err := plug.Stop(); err != nil {
@@ -74,6 +178,16 @@ func (e *Executor) cleanupProject() error {
 		e.srv.Stop()
 	}
 
+	if e.traceFile != nil {
+		client.SetTracer(nil)
+
+		if err := e.traceFile.Close(); err != nil {
+			return merry.Errorf("error closing trace file: %w", err)
+		}
+
+		e.traceFile = nil
+	}
+
 	return nil
 }
 
@@ -1,9 +1,11 @@
 package cmd
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/outblocks/outblocks-cli/pkg/config"
+	"github.com/spf13/cobra"
 )
 
 func TestAddPluginsCommandsSkipsUnloadedPlugins(t *testing.T) {
@@ -16,3 +18,67 @@ func TestAddPluginsCommandsSkipsUnloadedPlugins(t *testing.T) {
 		t.Fatalf("addPluginsCommands returned error: %v", err)
 	}
 }
+
+func TestCommandFlagsDoNotConflict(t *testing.T) {
+	e := NewExecutor()
+
+	var check func(cmd *cobra.Command)
+
+	check = func(cmd *cobra.Command) {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Errorf("conflicting flags of '%s': %v", cmd.CommandPath(), r)
+			}
+		}()
+
+		// Merging inherited flags panics when local flag redefines a global one or its shorthand.
+		cmd.InheritedFlags()
+		cmd.LocalFlags()
+
+		for _, c := range cmd.Commands() {
+			check(c)
+		}
+	}
+
+	check(e.rootCmd)
+}
+
+func TestBuildAppsLoadOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		targets []string
+	}{
+		{"all apps", []string{"run"}, nil},
+		{"targeted apps", []string{"run", "--target", "service.api"}, []string{"service.api"}},
+		{"positional targets", []string{"run", "service.api"}, []string{"service.api"}},
+		{"remote env loads all apps", []string{"run", "--target", "service.api", "--remote-env", "staging"}, nil},
+		{"exec command is not a target", []string{"exec", "service.api", "--", "python", "manage.py", "migrate"}, []string{"service.api"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, args, err := NewExecutor().rootCmd.Find(tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			opts, err := buildAppsLoadOptions(cmd, args)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var targets []string
+
+			if opts != nil {
+				for _, m := range opts.Targets.Unmatched() {
+					targets = append(targets, m.Input())
+				}
+			}
+
+			if strings.Join(targets, ",") != strings.Join(tt.targets, ",") {
+				t.Errorf("unexpected apps load targets: %v, want %v", targets, tt.targets)
+			}
+		})
+	}
+}
@@ -26,7 +26,7 @@ func (e *Executor) newInitCmd() *cobra.Command {
 				opts.Path = args[0]
 			}
 
-			return actions.NewInit(e.Log(), e.PluginsCacheDir(), e.srv.Addr().String(), opts).Run(cmd.Context())
+			return actions.NewInit(e.Log(), e.PluginsCacheDir(), e.pluginHost(), opts).Run(cmd.Context())
 		},
 	}
 
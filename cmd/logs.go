@@ -10,6 +10,7 @@ import (
 	"github.com/outblocks/outblocks-cli/pkg/actions"
 	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
 	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
 )
 
 func parseTimeOrDuration(in string) (time.Time, error) {
@@ -34,9 +35,11 @@ func (e *Executor) newLogsCmd() *cobra.Command {
 	opts := &actions.LogsOptions{}
 
 	var (
-		targets    []string
-		severity   string
-		start, end string
+		targets     []string
+		severity    string
+		start, end  string
+		query       string
+		listQueries bool
 	)
 
 	cmd := &cobra.Command{
@@ -50,6 +53,52 @@ func (e *Executor) newLogsCmd() *cobra.Command {
 		},
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if listQueries {
+				return actions.NewLogs(e.cfg, opts).ListQueries()
+			}
+
+			if query != "" {
+				q := e.cfg.Logs.Query(query)
+				if q == nil {
+					return merry.Errorf("unknown logs query specified: %s, check available queries with: ok logs --list-queries", query)
+				}
+
+				// Explicitly set flags override saved query.
+				f := cmd.Flags()
+
+				if !f.Changed("target") && len(args) == 0 {
+					targets = q.Targets
+				}
+
+				if !f.Changed("only-apps") && q.OnlyApps {
+					opts.OnlyApps = true
+				}
+
+				if !f.Changed("severity") && q.Severity != "" {
+					severity = q.Severity
+				}
+
+				if !f.Changed("contains") && len(q.Contains) != 0 {
+					opts.Contains = q.Contains
+				}
+
+				if !f.Changed("not-contains") && len(q.NotContains) != 0 {
+					opts.NotContains = q.NotContains
+				}
+
+				if !f.Changed("filter") && q.Filter != "" {
+					opts.Filter = q.Filter
+				}
+
+				if !f.Changed("start") && q.Start != "" {
+					start = q.Start
+				}
+
+				if !f.Changed("end") && q.End != "" {
+					end = q.End
+				}
+			}
+
 			opts.Targets = util.NewTargetMatcher()
 
 			targets = append(targets, args...)
@@ -97,6 +146,12 @@ func (e *Executor) newLogsCmd() *cobra.Command {
 				return merry.Errorf("while stream logs end time cannot be specified")
 			}
 
+			opts.Output = strings.ToLower(opts.Output)
+
+			if !slices.Contains(actions.LogsOutputs, opts.Output) {
+				return merry.Errorf("unknown output format specified: %s (options: %s)", opts.Output, strings.Join(actions.LogsOutputs, ", "))
+			}
+
 			return actions.NewLogs(e.cfg, opts).Run(cmd.Context())
 		},
 	}
@@ -109,8 +164,13 @@ func (e *Executor) newLogsCmd() *cobra.Command {
 	f.StringVarP(&severity, "severity", "l", "", "minimum severity level (options: debug, notice, info, warn, error)")
 	f.StringSliceVarP(&opts.Contains, "contains", "c", nil, "filter logs containing specific words")
 	f.StringSliceVarP(&opts.NotContains, "not-contains", "x", nil, "filter logs not containing specific words")
-	f.StringVarP(&opts.Filter, "filter", "q", "", "pass raw filter to logs, refer to cloud provider docs for possible options")
+	f.StringVarP(&opts.Filter, "filter", "q", "", "pass raw filter to logs, refer to cloud provider docs for possible options (regular expression for local logs)")
 	f.BoolVarP(&opts.Follow, "follow", "w", false, "stream logs (end has to be unspecified)")
+	f.StringVarP(&opts.Output, "output", "o", actions.LogsOutputText, "output format (options: text, json, logfmt, raw)")
+	f.StringVar(&opts.OutFile, "out-file", "", "additionally append logs to specified file, using the same output format")
+	f.BoolVar(&opts.Local, "local", false, "show logs stored during local runs instead of deployed ones")
+	f.StringVarP(&query, "query", "Q", "", "use saved query defined in project config under logs.queries, flags specified explicitly take precedence")
+	f.BoolVar(&listQueries, "list-queries", false, "list saved queries defined in project config under logs.queries")
 
 	return cmd
 }
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"github.com/outblocks/outblocks-cli/pkg/actions"
+	"github.com/outblocks/outblocks-cli/pkg/plugins"
 	"github.com/spf13/cobra"
 )
 
@@ -29,7 +30,7 @@ func (e *Executor) newPluginsCmd() *cobra.Command {
 		},
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return actions.NewPluginManager(e.Log(), e.cfg, e.loader, e.srv.Addr().String()).List(cmd.Context())
+			return actions.NewPluginManager(e.Log(), e.cfg, e.loader, e.pluginHost()).List(cmd.Context())
 		},
 	}
 
@@ -47,7 +48,7 @@ func (e *Executor) newPluginsCmd() *cobra.Command {
 		},
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return actions.NewPluginManager(e.Log(), e.cfg, e.loader, e.srv.Addr().String()).Update(cmd.Context(), updateForce)
+			return actions.NewPluginManager(e.Log(), e.cfg, e.loader, e.pluginHost()).Update(cmd.Context(), updateForce)
 		},
 	}
 
@@ -66,7 +67,7 @@ func (e *Executor) newPluginsCmd() *cobra.Command {
 		SilenceUsage: true,
 		Args:         cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return actions.NewPluginManager(e.Log(), e.cfg, e.loader, e.srv.Addr().String()).Add(cmd.Context(), args[0], addOpts)
+			return actions.NewPluginManager(e.Log(), e.cfg, e.loader, e.pluginHost()).Add(cmd.Context(), args[0], addOpts)
 		},
 	}
 
@@ -84,10 +85,95 @@ func (e *Executor) newPluginsCmd() *cobra.Command {
 		SilenceUsage: true,
 		Args:         cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return actions.NewPluginManager(e.Log(), e.cfg, e.loader, e.srv.Addr().String()).Delete(cmd.Context(), args[0])
+			return actions.NewPluginManager(e.Log(), e.cfg, e.loader, e.pluginHost()).Delete(cmd.Context(), args[0])
 		},
 	}
 
+	lockOpts := &actions.PluginManagerLockOptions{}
+
+	lock := &cobra.Command{
+		Use:   "lock",
+		Short: "Lock plugin checksums",
+		Long:  `Compute checksums of plugins for specified platforms and record them in lockfile so that they can be verified by teammates.`,
+		Annotations: map[string]string{
+			cmdGroupAnnotation:            cmdGroupMain,
+			cmdProjectLoadModeAnnotation:  cmdLoadModeEssential,
+			cmdAppsLoadModeAnnotation:     cmdLoadModeSkip,
+			cmdProjectSkipCheckAnnotation: "1",
+		},
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return actions.NewPluginManager(e.Log(), e.cfg, e.loader, e.pluginHost()).Lock(cmd.Context(), lockOpts)
+		},
+	}
+
+	vendorOpts := &actions.PluginManagerVendorOptions{}
+
+	vendor := &cobra.Command{
+		Use:   "vendor",
+		Short: "Vendor plugins",
+		Long:  `Copy locked plugins for specified platforms to project so that they can be loaded without downloading, e.g. in air-gapped CI.`,
+		Annotations: map[string]string{
+			cmdGroupAnnotation:            cmdGroupMain,
+			cmdProjectLoadModeAnnotation:  cmdLoadModeEssential,
+			cmdAppsLoadModeAnnotation:     cmdLoadModeSkip,
+			cmdProjectSkipCheckAnnotation: "1",
+		},
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return actions.NewPluginManager(e.Log(), e.cfg, e.loader, e.pluginHost()).Vendor(cmd.Context(), vendorOpts)
+		},
+	}
+
+	pruneOpts := &actions.PluginManagerPruneOptions{}
+
+	prune := &cobra.Command{
+		Use:   "prune",
+		Short: "Prune plugins cache",
+		Long: `Remove cached plugin versions not referenced by lockfile and clean up broken plugin links in project.
+By default only cached versions of plugins used by this project are pruned, so cache of other projects is left intact.
+With --all-projects plugins cache shared by all projects is pruned: every cached plugin version that is not in this project lockfile
+or lockfiles passed with --lockfile is removed, projects not passed will have to download their plugins again.`,
+		Annotations: map[string]string{
+			cmdGroupAnnotation:            cmdGroupMain,
+			cmdProjectLoadModeAnnotation:  cmdLoadModeEssential,
+			cmdAppsLoadModeAnnotation:     cmdLoadModeSkip,
+			cmdProjectSkipCheckAnnotation: "1",
+		},
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return actions.NewPluginManager(e.Log(), e.cfg, e.loader, e.pluginHost()).Prune(cmd.Context(), pruneOpts)
+		},
+	}
+
+	doctor := &cobra.Command{
+		Use:   "doctor [name]",
+		Short: "Diagnose plugins",
+		Long:  `Start plugins and check their executables, handshakes and gRPC services, listing actions, types and commands they provide.`,
+		Annotations: map[string]string{
+			cmdGroupAnnotation:            cmdGroupMain,
+			cmdProjectLoadModeAnnotation:  cmdLoadModeEssential,
+			cmdAppsLoadModeAnnotation:     cmdLoadModeSkip,
+			cmdProjectSkipCheckAnnotation: "1",
+		},
+		SilenceUsage: true,
+		Args:         cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var name string
+			if len(args) == 1 {
+				name = args[0]
+			}
+
+			return actions.NewPluginManager(e.Log(), e.cfg, e.loader, e.pluginHost()).Doctor(cmd.Context(), name)
+		},
+	}
+
+	prune.Flags().BoolVar(&pruneOpts.AllProjects, "all-projects", false, "DESTRUCTIVE: prune cache of all projects, removing plugin versions not locked by this project, including ones other projects need (pass their lockfiles with --lockfile to keep them)")
+	prune.Flags().StringSliceVar(&pruneOpts.Lockfiles, "lockfile", nil, "additional lockfiles with plugin versions to keep")
+	prune.Flags().BoolVar(&pruneOpts.DryRun, "dry-run", false, "only report what would be removed")
+	vendor.Flags().StringVar(&vendorOpts.Dir, "dir", plugins.DefaultVendorDir, "dir to vendor plugins to, relative to project dir")
+	vendor.Flags().StringSliceVar(&vendorOpts.Platforms, "platforms", nil, "platforms to vendor plugins for in a form of <os>/<arch>, e.g. linux/amd64,darwin/arm64 (defaults to current platform)")
+	lock.Flags().StringSliceVar(&lockOpts.Platforms, "platforms", nil, "platforms to compute checksums for in a form of <os>/<arch>, e.g. linux/amd64,darwin/arm64 (defaults to current platform)")
 	add.Flags().StringVarP(&addOpts.Source, "source", "s", "", "specify plugin source, only needed for plugins not created by Outblocks Team")
 	add.Flags().StringVarP(&addOpts.Version, "version", "v", "", "specify plugin version, defaults to latest available version")
 	update.Flags().BoolVar(&updateForce, "force", false, "force update, ignoring existing version constraints and update project YAML if needed")
@@ -97,6 +183,10 @@ func (e *Executor) newPluginsCmd() *cobra.Command {
 		list,
 		update,
 		del,
+		lock,
+		vendor,
+		prune,
+		doctor,
 	)
 
 	return cmd
@@ -336,6 +336,13 @@ func (e *Executor) newRoot() *cobra.Command {
 	f.StringVarP(&e.opts.env, "env", "e", "dev", "environment to use")
 	e.env.BindCLIFlag("env", f.Lookup("env"))
 
+	f.BoolVar(&e.opts.frozen, "frozen", false, "fail instead of downloading plugins that are not vendored, mirrored or cached")
+	e.env.BindCLIFlag("frozen", f.Lookup("frozen"))
+
+	f.StringToStringVar(&e.opts.pluginDev, "plugin-dev", nil, "use plugin from a local working dir for plugin development, e.g. --plugin-dev gcp=../cli-plugin-gcp")
+	f.StringVar(&e.opts.traceFile, "trace-file", "", "record every plugin call with its name, plugin, duration and status as JSON lines in file for performance analysis")
+	f.StringVar(&e.opts.answersFile, "answers", "", "YAML file with answers to plugin prompts keyed by prompt message, its key or /regex/, key is message uppercased with non-alphanumeric characters replaced by _ (answers can be also set with OUTBLOCKS_ANSWER_<KEY> env vars)")
+
 	f.Lookup("help").Hidden = true
 
 	cmd.SetUsageFunc(func(c *cobra.Command) error { return rootCmdUsageFunc(e.log, c) })
@@ -344,6 +351,7 @@ func (e *Executor) newRoot() *cobra.Command {
 	cmd.AddCommand(
 		e.newCompletionCmd(),
 		e.newRunCmd(),
+		e.newExecCmd(),
 		e.newDeployCmd(),
 		e.newPluginsCmd(),
 		e.newForceUnlockCmd(),
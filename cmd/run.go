@@ -1,15 +1,68 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"strings"
+
 	"github.com/outblocks/outblocks-cli/internal/util"
 	"github.com/outblocks/outblocks-cli/pkg/actions"
+	"github.com/outblocks/outblocks-cli/pkg/plugins"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
+// runFlags holds flags affecting allocated ports and urls, shared by commands that resolve local run environment.
+type runFlags struct {
+	opts           *actions.RunOptions
+	targets, skips []string
+}
+
+func (r *runFlags) register(f *pflag.FlagSet) {
+	f.StringSliceVarP(&r.targets, "target", "t", nil, "target only specified apps/dependencies, can specify multiple or separate values with comma in a form of <type>.<name>, e.g.: static.website,service.api,dep.database")
+	f.StringSliceVarP(&r.skips, "skip", "s", nil, "skip specified apps/dependencies (if they exist), can specify multiple or separate values with comma in a form of <type>.<name>, e.g.: static.website,service.api,dep.database")
+	f.StringVarP(&r.opts.ListenIP, "listen-ip", "l", "127.0.0.1", "local server ip to listen on")
+	f.IntVarP(&r.opts.ListenPort, "port", "p", 8000, "local server port")
+	f.StringVar(&r.opts.HostsSuffix, "hosts-suffix", ".local.test", "local hosts suffix to use for url matching")
+	f.BoolVar(&r.opts.HostsRouting, "hosts-routing", true, "adds local hosts and routes based on it, requires sudo/admin privilege")
+	f.StringVar(&r.opts.RemoteEnv, "remote-env", "", "use apps deployed in specified env for every app that is not run locally, e.g.: --remote-env staging --target service.api")
+}
+
+func (r *runFlags) prepare(ctx context.Context, e *Executor, targets []string) error {
+	r.opts.Targets = util.NewTargetMatcher()
+	r.opts.Skips = util.NewTargetMatcher()
+
+	for _, t := range append(r.targets, targets...) {
+		err := r.opts.Targets.Add(t)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, t := range r.skips {
+		err := r.opts.Skips.Add(t)
+		if err != nil {
+			return err
+		}
+	}
+
+	if r.opts.RemoteEnv != "" {
+		remoteCfg, err := e.loadEnvProject(ctx, r.opts.RemoteEnv, plugins.ActionState)
+		if err != nil {
+			return err
+		}
+
+		r.opts.RemoteState = remoteCfg.State
+	}
+
+	return nil
+}
+
 func (e *Executor) newRunCmd() *cobra.Command {
 	opts := &actions.RunOptions{}
+	flags := &runFlags{opts: opts}
 
-	var targets, skips []string
+	var onlyLogs []string
 
 	cmd := &cobra.Command{
 		Use:   "run",
@@ -23,22 +76,19 @@ func (e *Executor) newRunCmd() *cobra.Command {
 		},
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts.Targets = util.NewTargetMatcher()
-			opts.Skips = util.NewTargetMatcher()
-
-			targets = append(targets, args...)
-
-			for _, t := range targets {
-				err := opts.Targets.Add(t)
-				if err != nil {
-					return err
-				}
+			err := flags.prepare(cmd.Context(), e, args)
+			if err != nil {
+				return err
 			}
 
-			for _, t := range skips {
-				err := opts.Skips.Add(t)
-				if err != nil {
-					return err
+			if len(onlyLogs) > 0 {
+				opts.OnlyLogs = util.NewTargetMatcher()
+
+				for _, t := range onlyLogs {
+					err := opts.OnlyLogs.Add(t)
+					if err != nil {
+						return err
+					}
 				}
 			}
 
@@ -46,14 +96,51 @@ func (e *Executor) newRunCmd() *cobra.Command {
 		},
 	}
 
+	// Flags affecting allocated ports and urls are shared with subcommands.
+	flags.register(cmd.PersistentFlags())
+
 	f := cmd.Flags()
 	f.BoolVar(&opts.Direct, "direct", false, "force run all apps in local mode, directly running their commands")
-	f.StringSliceVarP(&targets, "target", "t", nil, "target only specified apps/dependencies, can specify multiple or separate values with comma in a form of <type>.<name>, e.g.: static.website,service.api,dep.database")
-	f.StringSliceVarP(&skips, "skip", "s", nil, "skip specified apps/dependencies (if they exist), can specify multiple or separate values with comma in a form of <type>.<name>, e.g.: static.website,service.api,dep.database")
-	f.StringVarP(&opts.ListenIP, "listen-ip", "l", "127.0.0.1", "local server ip to listen on")
-	f.IntVarP(&opts.ListenPort, "port", "p", 8000, "local server port")
-	f.StringVar(&opts.HostsSuffix, "hosts-suffix", ".local.test", "local hosts suffix to use for url matching")
-	f.BoolVar(&opts.HostsRouting, "hosts-routing", true, "adds local hosts and routes based on it, requires sudo/admin privilege")
+	f.StringVar(&opts.LogDir, "log-dir", "", "write stdout and stderr of every app/dependency to separate files in specified dir")
+	f.StringSliceVar(&onlyLogs, "only-logs", nil, "show output only from specified apps/dependencies while still running all of them, in a form of <type>.<name>, e.g.: service.api,dep.database")
+	f.BoolVar(&opts.LogTimestamps, "log-timestamps", false, "prefix every output line with a timestamp")
+	f.BoolVar(&opts.UI, "ui", false, "show interactive dashboard with state of every app and dependency, allowing to restart apps, tail their logs, open urls and log requests")
+	f.BoolVar(&opts.StaticRebuild, "static-rebuild", false, "rebuild static apps served directly from their build dir whenever their sources change")
+
+	envOpts := &actions.RunEnvOptions{}
+
+	env := &cobra.Command{
+		Use:   "env <app>",
+		Short: "Print local app environment",
+		Long: `Prints environment that app would receive when run directly, including allocated ports, without starting anything.
+Use --pin-ports to keep allocated ports stable between runs, pinned ports are stored in .outblocks/run-ports.json.
+Dependency vars are recorded in .outblocks/run-deps.json by 'ok run' and used as long as dependencies keep their ports.`,
+		Example: `ok run env service.api --format dotenv > .env`,
+		Annotations: map[string]string{
+			cmdGroupAnnotation:           cmdGroupMain,
+			cmdProjectLoadModeAnnotation: cmdLoadModeFull,
+			cmdAppsLoadModeAnnotation:    cmdLoadModeFull,
+			cmdSecretsLoadAnnotation:     "1",
+		},
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := flags.prepare(cmd.Context(), e, nil)
+			if err != nil {
+				return err
+			}
+
+			envOpts.App = args[0]
+
+			return actions.NewRun(e.log, e.cfg, opts).Env(cmd.Context(), cmd.OutOrStdout(), envOpts)
+		},
+	}
+
+	// No shorthand, -f is already taken by global --values flag.
+	env.Flags().StringVar(&envOpts.Format, "format", actions.RunEnvFormatDotenv, fmt.Sprintf("output format, one of: %s", strings.Join(actions.RunEnvFormats, ", ")))
+	env.Flags().BoolVar(&envOpts.PinPorts, "pin-ports", false, "pin currently allocated ports of all apps/dependencies so that next runs and exports use the same ones")
+
+	cmd.AddCommand(env)
 
 	return cmd
 }
@@ -1,8 +1,15 @@
 package cmd
 
 import (
+	"context"
+	"strings"
+
+	"github.com/ansel1/merry/v2"
 	"github.com/outblocks/outblocks-cli/pkg/actions"
+	"github.com/outblocks/outblocks-cli/pkg/config"
+	"github.com/outblocks/outblocks-cli/pkg/plugins"
 	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
 )
 
 func (e *Executor) newSecretsCmd() *cobra.Command {
@@ -81,12 +88,15 @@ func (e *Executor) newSecretsCmd() *cobra.Command {
 		},
 	}
 
-	var importFile string
+	var (
+		importOpts actions.SecretsImportOptions
+		exportOpts actions.SecretsExportOptions
+	)
 
 	imp := &cobra.Command{
 		Use:   "import",
 		Short: "Import secrets from file",
-		Long:  `Import secrets from YAML file.`,
+		Long:  `Import secrets from YAML, JSON or dotenv file. Format is detected from file name unless specified.`,
 		Annotations: map[string]string{
 			cmdGroupAnnotation:           cmdGroupMain,
 			cmdProjectLoadModeAnnotation: cmdLoadModeEssential,
@@ -94,7 +104,31 @@ func (e *Executor) newSecretsCmd() *cobra.Command {
 		},
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return actions.NewSecretsManager(e.Log(), e.cfg).Import(cmd.Context(), importFile)
+			if err := validateSecretsFormat(importOpts.Format); err != nil {
+				return err
+			}
+
+			return actions.NewSecretsManager(e.Log(), e.cfg).Import(cmd.Context(), &importOpts)
+		},
+	}
+
+	export := &cobra.Command{
+		Use:   "export",
+		Short: "Export secrets to file",
+		Long:  `Export secrets in YAML, JSON or dotenv format to a file or stdout.`,
+		Annotations: map[string]string{
+			cmdGroupAnnotation:           cmdGroupMain,
+			cmdProjectLoadModeAnnotation: cmdLoadModeEssential,
+			cmdAppsLoadModeAnnotation:    cmdLoadModeSkip,
+		},
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateSecretsFormat(exportOpts.Format); err != nil {
+				return err
+			}
+
+			return actions.NewSecretsManager(e.Log(), e.cfg).Export(cmd.Context(), &exportOpts)
 		},
 	}
 
@@ -133,17 +167,130 @@ func (e *Executor) newSecretsCmd() *cobra.Command {
 
 	destroy.Flags().BoolVar(&destroyForce, "force", false, "force destroy without prompt")
 
-	imp.Flags().StringVarP(&importFile, "file", "i", "", "secrets file to import")
+	var (
+		diffFrom, diffTo string
+		copyOpts         actions.SecretsCopyOptions
+	)
+
+	diff := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare secrets between environments",
+		Long:  `Show which secrets are missing, extra or differ in one environment compared to another. Values are never printed, not even in a hashed form.`,
+		Annotations: map[string]string{
+			cmdGroupAnnotation:           cmdGroupMain,
+			cmdProjectLoadModeAnnotation: cmdLoadModeEssential,
+			cmdAppsLoadModeAnnotation:    cmdLoadModeSkip,
+		},
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from, to, err := e.loadSecretsEnvs(cmd.Context(), diffFrom, diffTo)
+			if err != nil {
+				return err
+			}
+
+			return actions.NewSecretsManager(e.Log(), e.cfg).Diff(cmd.Context(), from, to)
+		},
+	}
+
+	cp := &cobra.Command{
+		Use:   "copy",
+		Short: "Copy secrets between environments",
+		Long:  `Copy secret values from one environment to another, e.g. to bootstrap a new environment.`,
+		Annotations: map[string]string{
+			cmdGroupAnnotation:           cmdGroupMain,
+			cmdProjectLoadModeAnnotation: cmdLoadModeEssential,
+			cmdAppsLoadModeAnnotation:    cmdLoadModeSkip,
+		},
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from, to, err := e.loadSecretsEnvs(cmd.Context(), diffFrom, diffTo)
+			if err != nil {
+				return err
+			}
+
+			return actions.NewSecretsManager(e.Log(), e.cfg).Copy(cmd.Context(), from, to, &copyOpts)
+		},
+	}
+
+	check := &cobra.Command{
+		Use:   "check",
+		Short: "Check secret references in config",
+		Long:  `Check that all secrets referenced in project and app config are defined. Unused secrets and secrets required by plugins that are not set are reported as warnings.`,
+		Annotations: map[string]string{
+			cmdGroupAnnotation:           cmdGroupMain,
+			cmdProjectLoadModeAnnotation: cmdLoadModeEssential,
+			cmdAppsLoadModeAnnotation:    cmdLoadModeSkip,
+		},
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return actions.NewSecretsManager(e.Log(), e.cfg).Check(cmd.Context())
+		},
+	}
+
+	for _, c := range []*cobra.Command{diff, cp} {
+		c.Flags().StringVar(&diffFrom, "from", "", "source environment")
+		c.Flags().StringVar(&diffTo, "to", "", "target environment, defaults to current environment")
+		_ = c.MarkFlagRequired("from")
+	}
+
+	cp.Flags().StringSliceVar(&copyOpts.Keys, "keys", nil, "copy only specified keys, can specify multiple or separate values with comma")
+	cp.Flags().BoolVar(&copyOpts.Overwrite, "overwrite", false, "overwrite secrets that already exist in target environment with a different value")
+
+	imp.Flags().StringVarP(&importOpts.File, "file", "i", "", "secrets file to import")
+	imp.Flags().StringVar(&importOpts.Format, "format", "", "import file format (options: yaml, json, dotenv), detected from file name by default")
+	imp.Flags().StringVar(&importOpts.Prefix, "prefix", "", "prefix imported keys with, only secrets with this prefix are replaced")
 	_ = imp.MarkFlagRequired("file")
 
+	export.Flags().StringVarP(&exportOpts.File, "file", "o", "", "file to export secrets to, defaults to stdout")
+	export.Flags().StringVar(&exportOpts.Format, "format", "", "export format (options: yaml, json, dotenv), detected from file name or yaml by default")
+	export.Flags().StringVar(&exportOpts.Prefix, "prefix", "", "export only secrets with this prefix, stripping it from keys")
+	export.Flags().BoolVar(&exportOpts.Force, "force", false, "allow printing secrets to a terminal")
+
 	cmd.AddCommand(
 		get,
 		set,
 		del,
 		edit,
 		imp,
+		export,
 		view,
+		diff,
+		cp,
+		check,
 	)
 
 	return cmd
 }
+
+func validateSecretsFormat(format string) error {
+	if format != "" && !slices.Contains(actions.SecretsFormats, format) {
+		return merry.Errorf("unknown secrets format: %s (options: %s)", format, strings.Join(actions.SecretsFormats, ", "))
+	}
+
+	return nil
+}
+
+func (e *Executor) loadSecretsEnvs(ctx context.Context, fromEnv, toEnv string) (from, to *config.Project, err error) {
+	if toEnv == "" {
+		toEnv = e.cfg.Env()
+	}
+
+	if fromEnv == toEnv {
+		return nil, nil, merry.Errorf("source and target environments have to differ")
+	}
+
+	from, err = e.loadEnvProject(ctx, fromEnv, plugins.ActionState, plugins.ActionSecrets)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	to, err = e.loadEnvProject(ctx, toEnv, plugins.ActionState, plugins.ActionSecrets)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return from, to, nil
+}
@@ -0,0 +1,193 @@
+// Package secretsfile implements secrets stored in an age encrypted YAML file that can be safely committed.
+package secretsfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/23doors/go-yaml"
+	"github.com/ansel1/merry/v2"
+	"github.com/outblocks/outblocks-cli/internal/fileutil"
+)
+
+const header = "# Encrypted secrets, manage with: ok secrets edit\n"
+
+type fileData struct {
+	Recipients []string `json:"recipients"`
+	Data       string   `json:"data"`
+}
+
+// LoadIdentities reads age identities from key file.
+func LoadIdentities(keyFile string) ([]age.Identity, error) {
+	f, err := os.Open(keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close() //nolint:errcheck
+
+	ids, err := age.ParseIdentities(f)
+	if err != nil {
+		return nil, merry.Errorf("cannot parse secrets key file %s: %w", keyFile, err)
+	}
+
+	return ids, nil
+}
+
+// GenerateIdentity creates new X25519 identity and writes it to key file readable only by its owner.
+func GenerateIdentity(keyFile string) (*age.X25519Identity, error) {
+	id, err := age.GenerateX25519Identity()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fileutil.MkdirAll(filepath.Dir(keyFile), 0o700); err != nil {
+		return nil, err
+	}
+
+	data := fmt.Sprintf("# public key: %s\n%s\n", id.Recipient(), id)
+
+	if err := os.WriteFile(keyFile, []byte(data), 0o600); err != nil {
+		return nil, merry.Errorf("cannot write secrets key file %s: %w", keyFile, err)
+	}
+
+	return id, nil
+}
+
+// IdentityRecipients returns public keys of X25519 identities.
+func IdentityRecipients(ids []age.Identity) []string {
+	var ret []string
+
+	for _, id := range ids {
+		if x, ok := id.(*age.X25519Identity); ok {
+			ret = append(ret, x.Recipient().String())
+		}
+	}
+
+	return ret
+}
+
+// Load decrypts secrets file, missing file is treated as empty.
+func Load(path string, ids []age.Identity) (map[string]string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return make(map[string]string), nil
+		}
+
+		return nil, err
+	}
+
+	var fd fileData
+
+	if err := yaml.Unmarshal(raw, &fd); err != nil {
+		return nil, merry.Errorf("cannot parse secrets file %s: %w", path, err)
+	}
+
+	if strings.TrimSpace(fd.Data) == "" {
+		return make(map[string]string), nil
+	}
+
+	r, err := age.Decrypt(armor.NewReader(strings.NewReader(fd.Data)), ids...)
+	if err != nil {
+		return nil, merry.Errorf("cannot decrypt secrets file %s, is your key one of its recipients? %w", path, err)
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	vals := make(map[string]string)
+
+	if err := yaml.Unmarshal(data, &vals); err != nil {
+		return nil, merry.Errorf("cannot parse decrypted secrets file %s: %w", path, err)
+	}
+
+	return vals, nil
+}
+
+// Save encrypts secrets for all recipients and writes them to path with mode 0600.
+func Save(path string, vals map[string]string, recipients []string) error {
+	if len(recipients) == 0 {
+		return merry.New("secrets file requires at least one recipient")
+	}
+
+	sort.Strings(recipients)
+
+	rs, err := age.ParseRecipients(strings.NewReader(strings.Join(recipients, "\n")))
+	if err != nil {
+		return merry.Errorf("invalid secrets recipient: %w", err)
+	}
+
+	plain, err := yaml.Marshal(vals)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+
+	aw := armor.NewWriter(&buf)
+
+	w, err := age.Encrypt(aw, rs...)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(plain); err != nil {
+		return err
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	if err := aw.Close(); err != nil {
+		return err
+	}
+
+	out, err := yaml.MarshalWithOptions(&fileData{
+		Recipients: recipients,
+		Data:       buf.String(),
+	}, yaml.UseLiteralStyleIfMultiline(true))
+	if err != nil {
+		return err
+	}
+
+	return writeFile(path, append([]byte(header), out...))
+}
+
+// writeFile replaces file atomically so that secrets are not lost if writing fails midway.
+// Temp file is created readable only by its owner.
+func writeFile(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(f.Name()) //nolint:errcheck
+
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(f.Name(), path); err != nil {
+		return err
+	}
+
+	return fileutil.ChownToUser(path)
+}
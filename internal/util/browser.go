@@ -0,0 +1,40 @@
+package util
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// OpenURL opens url in default browser, as a regular user when running with sudo.
+// Url is passed as a separate argument, it is never interpreted by shell.
+func OpenURL(u string) error {
+	var cmd *exec.Cmd
+
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", u)
+	case "darwin":
+		cmd = commandAsUser("open", u)
+	default:
+		cmd = commandAsUser("xdg-open", u)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	go func() {
+		_ = cmd.Wait()
+	}()
+
+	return nil
+}
+
+func commandAsUser(name string, args ...string) *exec.Cmd {
+	if uid, ok := os.LookupEnv("SUDO_UID"); ok && os.Geteuid() == 0 {
+		return exec.Command("sudo", append([]string{"-E", "-u", "#" + uid, name}, args...)...)
+	}
+
+	return exec.Command(name, args...)
+}
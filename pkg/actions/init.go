@@ -31,7 +31,7 @@ var errInitCanceled = errors.New("init canceled")
 type Init struct {
 	log            logger.Logger
 	pluginCacheDir string
-	hostAddr       string
+	host           plugins.Host
 	opts           *InitOptions
 	input          map[string]interface{}
 
@@ -67,11 +67,11 @@ func (o *InitOptions) Validate() error {
 	)
 }
 
-func NewInit(log logger.Logger, pluginCacheDir, hostAddr string, opts *InitOptions) *Init {
+func NewInit(log logger.Logger, pluginCacheDir string, host plugins.Host, opts *InitOptions) *Init {
 	return &Init{
 		log:            log,
 		pluginCacheDir: pluginCacheDir,
-		hostAddr:       hostAddr,
+		host:           host,
 		opts:           opts,
 	}
 }
@@ -528,7 +528,7 @@ func (d *Init) prompt(ctx context.Context, cfg *projectInit) error { //nolint:go
 		}
 	}
 
-	err := cfg.LoadPlugins(ctx, d.log, loader, d.hostAddr)
+	err := cfg.LoadPlugins(ctx, d.log, loader, d.host)
 	if err != nil {
 		return err
 	}
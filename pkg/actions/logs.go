@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 	"sort"
 	"strconv"
 	"strings"
@@ -13,16 +15,24 @@ import (
 	"github.com/outblocks/outblocks-cli/internal/util"
 	"github.com/outblocks/outblocks-cli/pkg/config"
 	"github.com/outblocks/outblocks-cli/pkg/logger"
+	"github.com/outblocks/outblocks-cli/pkg/plugins"
 	"github.com/outblocks/outblocks-cli/pkg/plugins/client"
 	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
+	"github.com/outblocks/outblocks-plugin-go/util/errgroup"
 	"github.com/pterm/pterm"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+const (
+	logsReorderWindow = 2 * time.Second
+	logsFlushInterval = 250 * time.Millisecond
+)
+
 type Logs struct {
 	log  logger.Logger
 	cfg  *config.Project
 	opts *LogsOptions
+	out  io.Writer
 }
 
 type LogsOptions struct {
@@ -34,6 +44,9 @@ type LogsOptions struct {
 	Severity              apiv1.LogSeverity
 	OnlyApps              bool
 	Follow                bool
+	Output                string
+	OutFile               string
+	Local                 bool
 }
 
 func NewLogs(cfg *config.Project, opts *LogsOptions) *Logs {
@@ -41,10 +54,11 @@ func NewLogs(cfg *config.Project, opts *LogsOptions) *Logs {
 		log:  logger.NewLogger(),
 		cfg:  cfg,
 		opts: opts,
+		out:  os.Stdout,
 	}
 }
 
-func (l *Logs) logCallback(idMap map[string]string) func(lr *apiv1.LogsResponse) {
+func normalizeLogsIDMap(idMap map[string]string) map[string]string {
 	normalizedMap := make(map[string]string, len(idMap))
 
 	mLen := 0
@@ -60,7 +74,50 @@ func (l *Logs) logCallback(idMap map[string]string) func(lr *apiv1.LogsResponse)
 		normalizedMap[k] = fmt.Sprintf(justify, v)
 	}
 
-	idMap = normalizedMap
+	return normalizedMap
+}
+
+func logTextLine(lr *apiv1.LogsResponse, idMap map[string]string) string {
+	parts := []string{pterm.Green(lr.Time.AsTime().Local().Format(time.StampMilli))}
+
+	if len(idMap) > 1 {
+		parts = append(parts, pterm.Yellow(idMap[lr.Source]))
+	}
+
+	if lr.Type == apiv1.LogsResponse_TYPE_STDERR {
+		parts = append(parts, pterm.Red("stderr"))
+	}
+
+	if lr.Http != nil {
+		httpStatusStr := strconv.Itoa(int(lr.Http.Status))
+		if lr.Http.Status >= 400 {
+			httpStatusStr = pterm.Style{pterm.Bold, pterm.FgRed, pterm.Underscore}.Sprint(httpStatusStr)
+		}
+
+		parts = append(parts, fmt.Sprintf("%s - %s %s %s %q %q", lr.Http.RemoteIp, lr.Http.RequestMethod, lr.Http.RequestUrl, httpStatusStr, lr.Http.Referer, lr.Http.UserAgent))
+	}
+
+	switch p := lr.Payload.(type) {
+	case *apiv1.LogsResponse_Text:
+		parts = append(parts, p.Text)
+	case *apiv1.LogsResponse_Json:
+		var fields []string
+
+		for k, v := range p.Json.AsMap() {
+			vv, _ := json.Marshal(v)
+			fields = append(fields, fmt.Sprintf("%s=%s", k, vv))
+		}
+
+		sort.Strings(fields)
+
+		parts = append(parts, fields...)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func (l *Logs) logCallback(idMap map[string]string) func(lr *apiv1.LogsResponse) {
+	idMap = normalizeLogsIDMap(idMap)
 
 	return func(lr *apiv1.LogsResponse) {
 		logFunc := l.log.Infof
@@ -78,46 +135,74 @@ func (l *Logs) logCallback(idMap map[string]string) func(lr *apiv1.LogsResponse)
 			logFunc = l.log.Errorf
 		}
 
-		parts := []string{pterm.Green(lr.Time.AsTime().Local().Format(time.StampMilli))}
+		logFunc("%s\n", logTextLine(lr, idMap))
+	}
+}
+
+// outputCallback returns callback printing log entries in requested output format and writing them to out file if needed.
+func (l *Logs) outputCallback(idMap map[string]string, sources map[string]logSource) (callback func(*apiv1.LogsResponse), closeFn func() error, err error) {
+	textCallback := l.logCallback(idMap)
+	normalizedIDMap := normalizeLogsIDMap(idMap)
+	closeFn = func() error { return nil }
 
-		if len(idMap) > 1 {
-			parts = append(parts, pterm.Yellow(idMap[lr.Source]))
-		}
+	var f *os.File
 
-		if lr.Type == apiv1.LogsResponse_TYPE_STDERR {
-			parts = append(parts, pterm.Red("stderr"))
+	if l.opts.OutFile != "" {
+		f, err = os.OpenFile(l.opts.OutFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, merry.Errorf("cannot open logs out file: %w", err)
 		}
 
-		if lr.Http != nil {
-			httpStatusStr := strconv.Itoa(int(lr.Http.Status))
-			if lr.Http.Status >= 400 {
-				httpStatusStr = pterm.Style{pterm.Bold, pterm.FgRed, pterm.Underscore}.Sprint(httpStatusStr)
+		closeFn = f.Close
+	}
+
+	format := func(lr *apiv1.LogsResponse) string {
+		if l.opts.Output == LogsOutputText {
+			line := pterm.RemoveColorFromString(logTextLine(lr, normalizedIDMap))
+
+			if sev, ok := logSeverityNames[lr.Severity]; ok {
+				line = fmt.Sprintf("%-6s %s", strings.ToUpper(sev), line)
 			}
 
-			parts = append(parts, fmt.Sprintf("%s - %s %s %s %q %q", lr.Http.RemoteIp, lr.Http.RequestMethod, lr.Http.RequestUrl, httpStatusStr, lr.Http.Referer, lr.Http.UserAgent))
+			return line
 		}
 
-		switch p := lr.Payload.(type) {
-		case *apiv1.LogsResponse_Text:
-			parts = append(parts, p.Text)
-		case *apiv1.LogsResponse_Json:
-			var fields []string
+		line, _ := newLogEntry(lr, sources).Format(l.opts.Output)
 
-			for k, v := range p.Json.AsMap() {
-				vv, _ := json.Marshal(v)
-				fields = append(fields, fmt.Sprintf("%s=%s", k, vv))
-			}
+		return line
+	}
+
+	callback = func(lr *apiv1.LogsResponse) {
+		if !logSeverityShown(lr.Severity, l.log.Level()) {
+			return
+		}
 
-			sort.Strings(fields)
+		if l.opts.Output == LogsOutputText {
+			textCallback(lr)
+		} else {
+			_, _ = fmt.Fprintln(l.out, format(lr))
+		}
 
-			parts = append(parts, fields...)
+		if f == nil {
+			return
 		}
 
-		logFunc("%s\n", strings.Join(parts, " "))
+		if _, err := fmt.Fprintln(f, format(lr)); err != nil {
+			l.log.Errorf("Cannot write to logs out file, disabling it: %s\n", err)
+
+			_ = f.Close()
+			f = nil
+		}
 	}
+
+	return callback, closeFn, nil
 }
 
 func (l *Logs) Run(ctx context.Context) error {
+	if l.opts.Local {
+		return l.runLocal(ctx)
+	}
+
 	yamlContext := &client.YAMLContext{
 		Prefix: "$.state",
 		Data:   l.cfg.YAMLData(),
@@ -139,11 +224,13 @@ func (l *Logs) Run(ctx context.Context) error {
 	)
 
 	idMap := make(map[string]string)
+	sources := make(map[string]logSource)
 
 	for _, app := range state.Apps {
 		if l.opts.Targets.IsEmpty() || l.opts.Targets.Matches(app.App.Id) {
 			apps = append(apps, app.App)
 			idMap[app.App.Id] = fmt.Sprintf("APP:%s:%s", app.App.Type, app.App.Name)
+			sources[app.App.Id] = logSource{App: fmt.Sprintf("%s.%s", app.App.Type, app.App.Name)}
 		}
 	}
 
@@ -152,6 +239,7 @@ func (l *Logs) Run(ctx context.Context) error {
 			if l.opts.Targets.IsEmpty() || l.opts.Targets.Matches(dep.Dependency.Id) {
 				deps = append(deps, dep.Dependency)
 				idMap[dep.Dependency.Id] = fmt.Sprintf("DEP:%s", dep.Dependency.Name)
+				sources[dep.Dependency.Id] = logSource{Dependency: dep.Dependency.Name}
 			}
 		}
 	}
@@ -160,31 +248,153 @@ func (l *Logs) Run(ctx context.Context) error {
 		return merry.Errorf("unknown target specified: '%s' is missing definition", t.Input())
 	}
 
-	// TODO: support multi-deployment plugins
-	var deployPluginName string
-	if len(apps) != 0 {
-		deployPluginName = apps[0].DeployPlugin
-	} else {
-		deployPluginName = deps[0].DeployPlugin
+	// Group targets by deploy plugin.
+	var pluginNames []string
+
+	reqs := make(map[string]*apiv1.LogsRequest)
+
+	getReq := func(name string) *apiv1.LogsRequest {
+		if req, ok := reqs[name]; ok {
+			return req
+		}
+
+		req := &apiv1.LogsRequest{
+			State:       state.Plugins[name].Proto(),
+			Start:       timestamppb.New(l.opts.Start),
+			End:         timestamppb.New(l.opts.End),
+			Severity:    l.opts.Severity,
+			Contains:    l.opts.Contains,
+			NotContains: l.opts.NotContains,
+			Filter:      l.opts.Filter,
+			Follow:      l.opts.Follow,
+		}
+
+		reqs[name] = req
+		pluginNames = append(pluginNames, name)
+
+		return req
+	}
+
+	for _, app := range apps {
+		req := getReq(app.DeployPlugin)
+		req.Apps = append(req.Apps, app)
+	}
+
+	for _, dep := range deps {
+		req := getReq(dep.DeployPlugin)
+		req.Dependencies = append(req.Dependencies, dep)
+	}
+
+	if len(pluginNames) == 0 {
+		return merry.Errorf("no apps and/or dependencies to show logs of")
+	}
+
+	sort.Strings(pluginNames)
+
+	deployPlugins := make([]*plugins.Plugin, len(pluginNames))
+
+	for i, name := range pluginNames {
+		deployPlugins[i] = l.cfg.FindLoadedPlugin(name)
+		if deployPlugins[i] == nil {
+			return merry.Errorf("deploy plugin '%s' not found", name)
+		}
+	}
+
+	callback, closeOut, err := l.outputCallback(idMap, sources)
+	if err != nil {
+		return err
+	}
+
+	defer closeOut() //nolint:errcheck
+
+	if len(deployPlugins) == 1 {
+		return deployPlugins[0].Client().Logs(ctx, reqs[pluginNames[0]], callback)
+	}
+
+	return l.mergedLogs(ctx, deployPlugins, reqs, callback)
+}
+
+// ListQueries lists saved log queries defined in project config.
+func (l *Logs) ListQueries() error {
+	queries := l.cfg.Logs.SortedQueries()
+	if len(queries) == 0 {
+		l.log.Println("No saved log queries found, define them in project config under logs.queries.")
+
+		return nil
+	}
+
+	data := [][]string{
+		{"Name", "Description", "Filters"},
+	}
+
+	for _, q := range queries {
+		var filters []string
+
+		add := func(name string, val string) {
+			if val != "" {
+				filters = append(filters, fmt.Sprintf("%s=%s", name, val))
+			}
+		}
+
+		add("targets", strings.Join(q.Targets, ","))
+
+		if q.OnlyApps {
+			add("only-apps", "true")
+		}
+
+		add("severity", q.Severity)
+		add("contains", strings.Join(q.Contains, ","))
+		add("not-contains", strings.Join(q.NotContains, ","))
+		add("filter", q.Filter)
+		add("start", q.Start)
+		add("end", q.End)
+
+		data = append(data, []string{
+			pterm.Yellow(q.Name),
+			q.Description,
+			strings.Join(filters, " "),
+		})
 	}
 
-	req := &apiv1.LogsRequest{
-		Apps:         apps,
-		Dependencies: deps,
-		State:        state.Plugins[deployPluginName].Proto(),
-		Start:        timestamppb.New(l.opts.Start),
-		End:          timestamppb.New(l.opts.End),
-		Severity:     l.opts.Severity,
-		Contains:     l.opts.Contains,
-		NotContains:  l.opts.NotContains,
-		Filter:       l.opts.Filter,
-		Follow:       l.opts.Follow,
+	return l.log.Table().WithHasHeader().WithData(pterm.TableData(data)).Render()
+}
+
+// mergedLogs queries logs of multiple deploy plugins concurrently, outputting them ordered by time.
+func (l *Logs) mergedLogs(ctx context.Context, deployPlugins []*plugins.Plugin, reqs map[string]*apiv1.LogsRequest, callback func(*apiv1.LogsResponse)) error {
+	merger := newLogsMerger(callback)
+	g, gctx := errgroup.WithContext(ctx)
+
+	for _, plug := range deployPlugins {
+		plug := plug
+
+		g.Go(func() error {
+			return plug.Client().Logs(gctx, reqs[plug.Name], merger.Add)
+		})
 	}
 
-	deployPlugin := l.cfg.FindLoadedPlugin(deployPluginName)
-	if deployPlugin == nil {
-		return merry.Errorf("deploy plugin '%s' not found", deployPluginName)
+	// When following, entries are held for a short while to be reordered with entries from other plugins.
+	done := make(chan struct{})
+
+	if l.opts.Follow {
+		go func() {
+			ticker := time.NewTicker(logsFlushInterval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-done:
+					return
+				case now := <-ticker.C:
+					merger.Flush(now.Add(-logsReorderWindow))
+				}
+			}
+		}()
 	}
 
-	return deployPlugin.Client().Logs(ctx, req, l.logCallback(idMap))
+	err := g.Wait()
+
+	close(done)
+	merger.FlushAll()
+
+	return err
 }
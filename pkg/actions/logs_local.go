@@ -0,0 +1,258 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ansel1/merry/v2"
+	"github.com/outblocks/outblocks-cli/pkg/actions/run"
+	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Keys checked for severity in JSON formatted lines, in order.
+var localLogsSeverityKeys = []string{"severity", "level", "lvl", "log.level"}
+
+var localLogsSeverities = map[string]apiv1.LogSeverity{
+	"trace":     apiv1.LogSeverity_LOG_SEVERITY_DEBUG,
+	"debug":     apiv1.LogSeverity_LOG_SEVERITY_DEBUG,
+	"notice":    apiv1.LogSeverity_LOG_SEVERITY_NOTICE,
+	"info":      apiv1.LogSeverity_LOG_SEVERITY_INFO,
+	"warn":      apiv1.LogSeverity_LOG_SEVERITY_WARN,
+	"warning":   apiv1.LogSeverity_LOG_SEVERITY_WARN,
+	"error":     apiv1.LogSeverity_LOG_SEVERITY_ERROR,
+	"err":       apiv1.LogSeverity_LOG_SEVERITY_ERROR,
+	"critical":  apiv1.LogSeverity_LOG_SEVERITY_ERROR,
+	"alert":     apiv1.LogSeverity_LOG_SEVERITY_ERROR,
+	"emergency": apiv1.LogSeverity_LOG_SEVERITY_ERROR,
+	"fatal":     apiv1.LogSeverity_LOG_SEVERITY_ERROR,
+	"panic":     apiv1.LogSeverity_LOG_SEVERITY_ERROR,
+}
+
+// localLogSource returns display name and source of app or dependency id as used in local log store.
+func localLogSource(id string) (name string, src logSource) {
+	if parts := strings.SplitN(id, "_", 3); len(parts) == 3 && parts[0] == "app" {
+		return fmt.Sprintf("APP:%s:%s", parts[1], parts[2]), logSource{App: fmt.Sprintf("%s.%s", parts[1], parts[2])}
+	}
+
+	if parts := strings.SplitN(id, "_", 2); len(parts) == 2 && parts[0] == "dep" {
+		return fmt.Sprintf("DEP:%s", parts[1]), logSource{Dependency: parts[1]}
+	}
+
+	return id, logSource{}
+}
+
+func localLogSeverity(fields map[string]interface{}) apiv1.LogSeverity {
+	for _, k := range localLogsSeverityKeys {
+		for fk, v := range fields {
+			if !strings.EqualFold(fk, k) {
+				continue
+			}
+
+			s, ok := v.(string)
+			if !ok {
+				continue
+			}
+
+			if sev, ok := localLogsSeverities[strings.ToLower(s)]; ok {
+				return sev
+			}
+		}
+	}
+
+	return apiv1.LogSeverity_LOG_SEVERITY_UNSPECIFIED
+}
+
+// localLogsResponse converts stored record to logs response, parsing JSON formatted lines to get severity and structured payload.
+func localLogsResponse(rec *run.LogRecord) *apiv1.LogsResponse {
+	lr := &apiv1.LogsResponse{
+		Source: rec.Source,
+		Time:   timestamppb.New(rec.Time),
+		Type:   apiv1.LogsResponse_TYPE_STDOUT,
+	}
+
+	if rec.Stream == run.LogStreamStderr {
+		lr.Type = apiv1.LogsResponse_TYPE_STDERR
+	}
+
+	msg := strings.TrimSpace(rec.Message)
+
+	if strings.HasPrefix(msg, "{") {
+		var fields map[string]interface{}
+
+		if json.Unmarshal([]byte(msg), &fields) == nil {
+			if st, err := structpb.NewStruct(fields); err == nil {
+				lr.Severity = localLogSeverity(fields)
+				lr.Payload = &apiv1.LogsResponse_Json{Json: st}
+
+				return lr
+			}
+		}
+	}
+
+	lr.Payload = &apiv1.LogsResponse_Text{Text: rec.Message}
+
+	return lr
+}
+
+type localLogsFilter struct {
+	opts   *LogsOptions
+	filter *regexp.Regexp
+}
+
+func newLocalLogsFilter(opts *LogsOptions) (*localLogsFilter, error) {
+	f := &localLogsFilter{
+		opts: opts,
+	}
+
+	if opts.Filter != "" {
+		var err error
+
+		f.filter, err = regexp.Compile(opts.Filter)
+		if err != nil {
+			return nil, merry.Errorf("invalid filter, for local logs it has to be a regular expression: %w", err)
+		}
+	}
+
+	return f, nil
+}
+
+func (f *localLogsFilter) Match(rec *run.LogRecord, lr *apiv1.LogsResponse) bool {
+	if !f.opts.Start.IsZero() && rec.Time.Before(f.opts.Start) {
+		return false
+	}
+
+	if !f.opts.End.IsZero() && rec.Time.After(f.opts.End) {
+		return false
+	}
+
+	// Lines without recognized severity are treated as info.
+	sev := lr.Severity
+	if sev == apiv1.LogSeverity_LOG_SEVERITY_UNSPECIFIED {
+		sev = apiv1.LogSeverity_LOG_SEVERITY_INFO
+	}
+
+	if sev < f.opts.Severity {
+		return false
+	}
+
+	for _, c := range f.opts.Contains {
+		if !strings.Contains(rec.Message, c) {
+			return false
+		}
+	}
+
+	for _, c := range f.opts.NotContains {
+		if strings.Contains(rec.Message, c) {
+			return false
+		}
+	}
+
+	if f.filter != nil && !f.filter.MatchString(rec.Message) {
+		return false
+	}
+
+	return true
+}
+
+// runLocal shows logs persisted during local `ok run` sessions.
+func (l *Logs) runLocal(ctx context.Context) error {
+	dir := LocalLogsPath(l.cfg)
+
+	all, err := run.LogStoreSources(dir)
+	if err != nil {
+		return merry.Errorf("cannot read local logs: %w", err)
+	}
+
+	var sources []string
+
+	idMap := make(map[string]string)
+	logSources := make(map[string]logSource)
+
+	match := func(id string) bool {
+		if l.opts.OnlyApps && !strings.HasPrefix(id, "app_") {
+			return false
+		}
+
+		return l.opts.Targets.IsEmpty() || l.opts.Targets.Matches(id)
+	}
+
+	for _, id := range all {
+		if !match(id) {
+			continue
+		}
+
+		sources = append(sources, id)
+		idMap[id], logSources[id] = localLogSource(id)
+	}
+
+	for _, t := range l.opts.Targets.Unmatched() {
+		return merry.Errorf("unknown target specified: '%s' has no local logs", t.Input())
+	}
+
+	if len(sources) == 0 {
+		return merry.Errorf("no local logs found, they are stored when running apps locally with: ok run")
+	}
+
+	filter, err := newLocalLogsFilter(l.opts)
+	if err != nil {
+		return err
+	}
+
+	callback, closeOut, err := l.outputCallback(idMap, logSources)
+	if err != nil {
+		return err
+	}
+
+	// Output callback is replaced when new sources are followed.
+	defer func() { _ = closeOut() }()
+
+	handle := func(rec *run.LogRecord) {
+		lr := localLogsResponse(rec)
+
+		if filter.Match(rec, lr) {
+			callback(lr)
+		}
+	}
+
+	recs, offsets, err := run.ReadLogStore(dir, sources)
+	if err != nil {
+		return merry.Errorf("cannot read local logs: %w", err)
+	}
+
+	for _, rec := range recs {
+		handle(rec)
+	}
+
+	if !l.opts.Follow {
+		return nil
+	}
+
+	// Sources that appear while following, e.g. apps started later, need to be added to output.
+	followMatch := func(id string) bool {
+		if !match(id) {
+			return false
+		}
+
+		idMap[id], logSources[id] = localLogSource(id)
+
+		cb, cl, err := l.outputCallback(idMap, logSources)
+		if err != nil {
+			l.log.Errorf("Cannot follow local logs of '%s': %s\n", id, err)
+
+			return false
+		}
+
+		_ = closeOut()
+		callback, closeOut = cb, cl
+
+		return true
+	}
+
+	// Follow from where reading stopped so that records written in between are not lost.
+	return run.FollowLogStore(ctx, dir, offsets, followMatch, handle)
+}
@@ -0,0 +1,94 @@
+package actions
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
+)
+
+// logsMerger merges log entries coming from multiple plugins ordering them by time.
+// Entries are held until flushed so that entries from slower streams can still be put in order.
+type logsMerger struct {
+	out func(*apiv1.LogsResponse)
+
+	mu      sync.Mutex
+	entries logsHeap
+	seq     int
+}
+
+type logsEntry struct {
+	res     *apiv1.LogsResponse
+	arrived time.Time
+	seq     int
+}
+
+type logsHeap []*logsEntry
+
+func (h logsHeap) Len() int { return len(h) }
+
+func (h logsHeap) Less(i, j int) bool {
+	ti, tj := h[i].res.Time.AsTime(), h[j].res.Time.AsTime()
+	if ti.Equal(tj) {
+		return h[i].seq < h[j].seq
+	}
+
+	return ti.Before(tj)
+}
+
+func (h logsHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *logsHeap) Push(x interface{}) { *h = append(*h, x.(*logsEntry)) }
+
+func (h *logsHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+
+	return e
+}
+
+func newLogsMerger(out func(*apiv1.LogsResponse)) *logsMerger {
+	return &logsMerger{
+		out: out,
+	}
+}
+
+func (m *logsMerger) Add(res *apiv1.LogsResponse) {
+	m.addAt(res, time.Now())
+}
+
+func (m *logsMerger) addAt(res *apiv1.LogsResponse, arrived time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.seq++
+
+	heap.Push(&m.entries, &logsEntry{
+		res:     res,
+		arrived: arrived,
+		seq:     m.seq,
+	})
+}
+
+// Flush outputs entries in order of their time as long as the earliest one arrived before given time.
+func (m *logsMerger) Flush(arrivedBefore time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for m.entries.Len() > 0 && m.entries[0].arrived.Before(arrivedBefore) {
+		m.out(heap.Pop(&m.entries).(*logsEntry).res)
+	}
+}
+
+func (m *logsMerger) FlushAll() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for m.entries.Len() > 0 {
+		m.out(heap.Pop(&m.entries).(*logsEntry).res)
+	}
+}
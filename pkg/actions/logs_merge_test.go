@@ -0,0 +1,46 @@
+package actions
+
+import (
+	"testing"
+	"time"
+
+	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
+	"golang.org/x/exp/slices"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestLogsMerger(t *testing.T) {
+	base := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := time.Now()
+
+	var out []string
+
+	m := newLogsMerger(func(r *apiv1.LogsResponse) {
+		out = append(out, r.Source)
+	})
+
+	add := func(source string, offset time.Duration, arrived time.Time) {
+		m.addAt(&apiv1.LogsResponse{Source: source, Time: timestamppb.New(base.Add(offset))}, arrived)
+	}
+
+	add("b", 2*time.Second, now)
+	add("a", 1*time.Second, now)
+	add("c", 3*time.Second, now.Add(5*time.Second))
+	add("a2", 1*time.Second, now)
+
+	// Only entries that arrived before flush time are output, earliest first.
+	m.Flush(now.Add(time.Second))
+
+	if got, want := out, []string{"a", "a2", "b"}; !slices.Equal(got, want) {
+		t.Fatalf("unexpected flush order: got %v want %v", got, want)
+	}
+
+	// Late entry with earlier time is still put before held ones.
+	add("z", 0, now.Add(6*time.Second))
+
+	m.FlushAll()
+
+	if got, want := out, []string{"a", "a2", "b", "z", "c"}; !slices.Equal(got, want) {
+		t.Fatalf("unexpected flush all order: got %v want %v", got, want)
+	}
+}
@@ -0,0 +1,202 @@
+package actions
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/outblocks/outblocks-cli/pkg/logger"
+	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	LogsOutputText   = "text"
+	LogsOutputJSON   = "json"
+	LogsOutputLogfmt = "logfmt"
+	LogsOutputRaw    = "raw"
+)
+
+var LogsOutputs = []string{LogsOutputText, LogsOutputJSON, LogsOutputLogfmt, LogsOutputRaw}
+
+var logSeverityNames = map[apiv1.LogSeverity]string{
+	apiv1.LogSeverity_LOG_SEVERITY_DEBUG:  "debug",
+	apiv1.LogSeverity_LOG_SEVERITY_NOTICE: "notice",
+	apiv1.LogSeverity_LOG_SEVERITY_INFO:   "info",
+	apiv1.LogSeverity_LOG_SEVERITY_WARN:   "warn",
+	apiv1.LogSeverity_LOG_SEVERITY_ERROR:  "error",
+}
+
+var logSeverityLevels = map[apiv1.LogSeverity]logger.LogLevel{
+	apiv1.LogSeverity_LOG_SEVERITY_DEBUG:  logger.LogLevelDebug,
+	apiv1.LogSeverity_LOG_SEVERITY_NOTICE: logger.LogLevelNotice,
+	apiv1.LogSeverity_LOG_SEVERITY_INFO:   logger.LogLevelInfo,
+	apiv1.LogSeverity_LOG_SEVERITY_WARN:   logger.LogLevelWarn,
+	apiv1.LogSeverity_LOG_SEVERITY_ERROR:  logger.LogLevelError,
+}
+
+// logSeverityShown returns true if log entry with given severity passes log level filter, the same way text output does.
+// Entries without severity are treated as info.
+func logSeverityShown(sev apiv1.LogSeverity, level logger.LogLevel) bool {
+	lvl, ok := logSeverityLevels[sev]
+	if !ok {
+		lvl = logger.LogLevelInfo
+	}
+
+	return lvl >= level
+}
+
+// logSource describes app or dependency that log entry comes from.
+type logSource struct {
+	App        string
+	Dependency string
+}
+
+type logEntry struct {
+	Time       time.Time              `json:"timestamp"`
+	Severity   string                 `json:"severity,omitempty"`
+	Source     string                 `json:"source"`
+	App        string                 `json:"app,omitempty"`
+	Dependency string                 `json:"dependency,omitempty"`
+	Stream     string                 `json:"stream,omitempty"`
+	HTTP       json.RawMessage        `json:"http,omitempty"`
+	Message    string                 `json:"message,omitempty"`
+	Payload    map[string]interface{} `json:"payload,omitempty"`
+}
+
+func newLogEntry(lr *apiv1.LogsResponse, sources map[string]logSource) *logEntry {
+	e := &logEntry{
+		Time:       lr.Time.AsTime(),
+		Severity:   logSeverityNames[lr.Severity],
+		Source:     lr.Source,
+		App:        sources[lr.Source].App,
+		Dependency: sources[lr.Source].Dependency,
+	}
+
+	switch lr.Type {
+	case apiv1.LogsResponse_TYPE_STDOUT:
+		e.Stream = "stdout"
+	case apiv1.LogsResponse_TYPE_STDERR:
+		e.Stream = "stderr"
+	case apiv1.LogsResponse_TYPE_UNSPECIFIED:
+	}
+
+	if lr.Http != nil {
+		e.HTTP, _ = protojson.MarshalOptions{UseProtoNames: true}.Marshal(lr.Http)
+	}
+
+	switch p := lr.Payload.(type) {
+	case *apiv1.LogsResponse_Text:
+		e.Message = p.Text
+	case *apiv1.LogsResponse_Json:
+		e.Payload = p.Json.AsMap()
+	}
+
+	return e
+}
+
+func (e *logEntry) JSON() (string, error) {
+	data, err := json.Marshal(e)
+
+	return string(data), err
+}
+
+func (e *logEntry) Logfmt() string {
+	var b strings.Builder
+
+	write := func(k, v string) {
+		if v == "" {
+			return
+		}
+
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(logfmtValue(v))
+	}
+
+	write("ts", e.Time.Format(time.RFC3339Nano))
+	write("severity", e.Severity)
+	write("source", e.Source)
+	write("app", e.App)
+	write("dependency", e.Dependency)
+	write("stream", e.Stream)
+
+	if len(e.HTTP) > 0 {
+		var http map[string]interface{}
+
+		_ = json.Unmarshal(e.HTTP, &http)
+
+		for _, k := range sortedMapKeys(http) {
+			write("http."+k, logfmtAny(http[k]))
+		}
+	}
+
+	write("msg", e.Message)
+
+	for _, k := range sortedMapKeys(e.Payload) {
+		write(k, logfmtAny(e.Payload[k]))
+	}
+
+	return b.String()
+}
+
+// Raw returns log message alone, structured payload is returned as compact json.
+func (e *logEntry) Raw() string {
+	if e.Payload != nil {
+		data, _ := json.Marshal(e.Payload)
+
+		return string(data)
+	}
+
+	return e.Message
+}
+
+func sortedMapKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func logfmtAny(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+
+	data, _ := json.Marshal(v)
+
+	return string(data)
+}
+
+func logfmtValue(v string) string {
+	if strings.ContainsAny(v, " =\"\t\n\r") {
+		return strconv.Quote(v)
+	}
+
+	return v
+}
+
+func (e *logEntry) Format(output string) (string, error) {
+	switch output {
+	case LogsOutputJSON:
+		return e.JSON()
+	case LogsOutputLogfmt:
+		return e.Logfmt(), nil
+	case LogsOutputRaw:
+		return e.Raw(), nil
+	}
+
+	return "", fmt.Errorf("unknown output format: %s", output)
+}
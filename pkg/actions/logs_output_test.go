@@ -0,0 +1,109 @@
+package actions
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/outblocks/outblocks-cli/pkg/logger"
+	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
+	plugin_util "github.com/outblocks/outblocks-plugin-go/util"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestLogEntryFormat(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	sources := map[string]logSource{
+		"app_1": {App: "service.api"},
+	}
+
+	text := newLogEntry(&apiv1.LogsResponse{
+		Source:   "app_1",
+		Time:     timestamppb.New(ts),
+		Severity: apiv1.LogSeverity_LOG_SEVERITY_WARN,
+		Type:     apiv1.LogsResponse_TYPE_STDERR,
+		Payload:  &apiv1.LogsResponse_Text{Text: "hello world"},
+	}, sources)
+
+	structured := newLogEntry(&apiv1.LogsResponse{
+		Source:  "dep_db",
+		Time:    timestamppb.New(ts),
+		Payload: &apiv1.LogsResponse_Json{Json: plugin_util.MustNewStruct(map[string]interface{}{"b": 1, "a": "x y"})},
+	}, map[string]logSource{"dep_db": {Dependency: "db"}})
+
+	tests := []struct {
+		entry  *logEntry
+		output string
+		want   string
+	}{
+		{text, LogsOutputJSON, `{"timestamp":"2024-01-02T03:04:05Z","severity":"warn","source":"app_1","app":"service.api","stream":"stderr","message":"hello world"}`},
+		{text, LogsOutputLogfmt, `ts=2024-01-02T03:04:05Z severity=warn source=app_1 app=service.api stream=stderr msg="hello world"`},
+		{text, LogsOutputRaw, `hello world`},
+		{structured, LogsOutputJSON, `{"timestamp":"2024-01-02T03:04:05Z","source":"dep_db","dependency":"db","payload":{"a":"x y","b":1}}`},
+		{structured, LogsOutputLogfmt, `ts=2024-01-02T03:04:05Z source=dep_db dependency=db a="x y" b=1`},
+		{structured, LogsOutputRaw, `{"a":"x y","b":1}`},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.entry.Format(tt.output)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.output, err)
+		}
+
+		if got != tt.want {
+			t.Errorf("%s:\n got: %s\nwant: %s", tt.output, got, tt.want)
+		}
+	}
+
+	if _, err := text.Format("yaml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestLogsOutputLevelFilter(t *testing.T) {
+	log := logger.NewLogger()
+	log.SetLevel(logger.LogLevelWarn)
+
+	outFile := filepath.Join(t.TempDir(), "logs.txt")
+
+	for _, output := range []string{LogsOutputJSON, LogsOutputLogfmt, LogsOutputRaw} {
+		var buf bytes.Buffer
+
+		l := &Logs{log: log, opts: &LogsOptions{Output: output, OutFile: outFile}, out: &buf}
+
+		callback, closeFn, err := l.outputCallback(map[string]string{"app_1": "api"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, sev := range []apiv1.LogSeverity{
+			apiv1.LogSeverity_LOG_SEVERITY_DEBUG,
+			apiv1.LogSeverity_LOG_SEVERITY_UNSPECIFIED,
+			apiv1.LogSeverity_LOG_SEVERITY_INFO,
+			apiv1.LogSeverity_LOG_SEVERITY_WARN,
+			apiv1.LogSeverity_LOG_SEVERITY_ERROR,
+		} {
+			callback(&apiv1.LogsResponse{Source: "app_1", Time: timestamppb.Now(), Severity: sev, Payload: &apiv1.LogsResponse_Text{Text: sev.String()}})
+		}
+
+		if err := closeFn(); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := strings.Count(buf.String(), "\n"); got != 2 || !strings.Contains(buf.String(), "LOG_SEVERITY_WARN") || !strings.Contains(buf.String(), "LOG_SEVERITY_ERROR") {
+			t.Errorf("%s: expected only warn and error entries, got:\n%s", output, buf.String())
+		}
+	}
+
+	data, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Count(string(data), "\n"); got != 6 {
+		t.Errorf("expected only warn and error entries in out file, got:\n%s", data)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/23doors/go-yaml"
@@ -12,29 +14,33 @@ import (
 	"github.com/23doors/go-yaml/parser"
 	"github.com/Masterminds/semver"
 	"github.com/ansel1/merry/v2"
+	"github.com/docker/go-units"
 	"github.com/outblocks/outblocks-cli/internal/fileutil"
 	"github.com/outblocks/outblocks-cli/internal/util"
 	"github.com/outblocks/outblocks-cli/pkg/config"
+	"github.com/outblocks/outblocks-cli/pkg/lockfile"
 	"github.com/outblocks/outblocks-cli/pkg/logger"
 	"github.com/outblocks/outblocks-cli/pkg/plugins"
+	"github.com/outblocks/outblocks-cli/pkg/plugins/client"
+	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
 	"github.com/pterm/pterm"
 	"golang.org/x/exp/slices"
 	"google.golang.org/grpc/codes"
 )
 
 type PluginManager struct {
-	log      logger.Logger
-	loader   *plugins.Loader
-	cfg      *config.Project
-	hostAddr string
+	log    logger.Logger
+	loader *plugins.Loader
+	cfg    *config.Project
+	host   plugins.Host
 }
 
-func NewPluginManager(log logger.Logger, cfg *config.Project, loader *plugins.Loader, hostAddr string) *PluginManager {
+func NewPluginManager(log logger.Logger, cfg *config.Project, loader *plugins.Loader, host plugins.Host) *PluginManager {
 	return &PluginManager{
-		log:      log,
-		cfg:      cfg,
-		loader:   loader,
-		hostAddr: hostAddr,
+		log:    log,
+		cfg:    cfg,
+		loader: loader,
+		host:   host,
 	}
 }
 
@@ -196,7 +202,7 @@ func (m *PluginManager) Add(ctx context.Context, name string, opts *PluginManage
 
 	m.cfg.Plugins = append(m.cfg.Plugins, plug)
 
-	err := m.cfg.LoadPlugins(ctx, m.log, m.loader, m.hostAddr)
+	err := m.cfg.LoadPlugins(ctx, m.log, m.loader, m.host)
 	if err != nil {
 		return err
 	}
@@ -296,6 +302,352 @@ func (m *PluginManager) Delete(ctx context.Context, name string) error {
 	return nil
 }
 
+type PluginManagerLockOptions struct {
+	Platforms []string
+}
+
+// platformArchs converts platforms to plugin archs, defaulting to current one.
+func platformArchs(platforms []string) ([]string, error) {
+	archs := make([]string, 0, len(platforms))
+
+	for _, platform := range platforms {
+		arch, err := plugins.PlatformArch(platform)
+		if err != nil {
+			return nil, err
+		}
+
+		archs = append(archs, arch)
+	}
+
+	if len(archs) == 0 {
+		archs = append(archs, plugins.CurrentArch())
+	}
+
+	return archs, nil
+}
+
+// Lock records checksums of plugins for specified platforms in lockfile so that they can be verified on any of them.
+func (m *PluginManager) Lock(ctx context.Context, opts *PluginManagerLockOptions) error {
+	archs, err := platformArchs(opts.Platforms)
+	if err != nil {
+		return err
+	}
+
+	prog, _ := m.log.ProgressBar().WithTotal(len(m.cfg.Plugins) * len(archs)).WithTitle("Computing plugin checksums...").Start()
+
+	var locked int
+
+	for _, p := range m.cfg.Plugins {
+		plug := p.Loaded()
+
+		if plug.IsDev() {
+			prog.Add(len(archs))
+			m.log.Warnf("Skipping development plugin '%s' as it is not pinned in lockfile.\n", p.Name)
+
+			continue
+		}
+
+		for _, arch := range archs {
+			prog.UpdateTitle(fmt.Sprintf("Computing plugin checksums: %s (%s)", p.Name, arch))
+
+			sum, err := m.loader.Checksum(ctx, p.Name, p.Source, plug.Version, arch)
+			if err != nil {
+				prog.Stop()
+
+				return err
+			}
+
+			if err := plug.SetChecksum(arch, sum); err != nil {
+				prog.Stop()
+
+				return err
+			}
+
+			prog.Increment()
+		}
+
+		locked++
+	}
+
+	m.log.Successf("Locked checksums of %d plugins for: %s.\n", locked, strings.Join(archs, ", "))
+
+	return nil
+}
+
+type PluginManagerVendorOptions struct {
+	Dir       string
+	Platforms []string
+}
+
+// Vendor copies locked plugins for specified platforms to dir so that they can be loaded without downloading them.
+func (m *PluginManager) Vendor(ctx context.Context, opts *PluginManagerVendorOptions) error {
+	archs, err := platformArchs(opts.Platforms)
+	if err != nil {
+		return err
+	}
+
+	dir := opts.Dir
+	if dir == "" {
+		dir = plugins.DefaultVendorDir
+	}
+
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(m.cfg.Dir, filepath.FromSlash(dir))
+	}
+
+	prog, _ := m.log.ProgressBar().WithTotal(len(m.cfg.Plugins) * len(archs)).WithTitle("Vendoring plugins...").Start()
+
+	var vendored int
+
+	for _, p := range m.cfg.Plugins {
+		plug := p.Loaded()
+
+		if plug.IsDev() {
+			prog.Add(len(archs))
+			m.log.Warnf("Skipping development plugin '%s'.\n", p.Name)
+
+			continue
+		}
+
+		for _, arch := range archs {
+			prog.UpdateTitle(fmt.Sprintf("Vendoring plugins: %s (%s)", p.Name, arch))
+
+			sum, err := m.loader.VendorPlugin(ctx, plug, p.Name, p.Source, arch, dir)
+			if err != nil {
+				prog.Stop()
+
+				return err
+			}
+
+			if err := plug.SetChecksum(arch, sum); err != nil {
+				prog.Stop()
+
+				return err
+			}
+
+			prog.Increment()
+		}
+
+		vendored++
+	}
+
+	m.log.Successf("Vendored %d plugins for %s to: %s.\n", vendored, strings.Join(archs, ", "), dir)
+
+	if dir != filepath.Join(m.cfg.Dir, filepath.FromSlash(plugins.DefaultVendorDir)) {
+		m.log.Infof("Set %s=%s to load plugins from it.\n", "OUTBLOCKS_PLUGIN_MIRROR", dir)
+	}
+
+	return nil
+}
+
+type PluginManagerPruneOptions struct {
+	AllProjects bool
+	Lockfiles   []string
+	DryRun      bool
+}
+
+// Prune removes cached plugin versions not referenced by current or specified lockfiles and cleans up broken plugin links in project.
+func (m *PluginManager) Prune(_ context.Context, opts *PluginManagerPruneOptions) error {
+	keep := m.cfg.Lockfile().Plugins
+
+	for _, f := range opts.Lockfiles {
+		lock, err := lockfile.LoadLockfile(f)
+		if err != nil {
+			return err
+		}
+
+		keep = append(keep, lock.Plugins...)
+	}
+
+	pruned, err := m.loader.Prune(&plugins.PruneOptions{
+		Keep:       keep,
+		AllPlugins: opts.AllProjects,
+		DryRun:     opts.DryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(pruned) == 0 {
+		m.log.Println("Nothing to prune.")
+
+		return nil
+	}
+
+	data := [][]string{
+		{"Path", "Reason", "Size"},
+	}
+
+	var total int64
+
+	for _, p := range pruned {
+		total += p.Size
+
+		data = append(data, []string{p.Path, p.Reason, units.HumanSize(float64(p.Size))})
+	}
+
+	if err := m.log.Table().WithHasHeader().WithData(pterm.TableData(data)).Render(); err != nil {
+		return err
+	}
+
+	if opts.DryRun {
+		m.log.Infof("Would remove %d plugin dirs and links, reclaiming %s.\n", len(pruned), units.HumanSize(float64(total)))
+	} else {
+		m.log.Successf("Removed %d plugin dirs and links, reclaimed %s.\n", len(pruned), units.HumanSize(float64(total)))
+	}
+
+	return nil
+}
+
+// Doctor starts plugins, or a single plugin by name, and reports problems with their executables, handshakes and gRPC services
+// along with actions, types and commands they provide.
+func (m *PluginManager) Doctor(ctx context.Context, name string) error {
+	plugs := m.cfg.Plugins
+
+	if name != "" {
+		found := m.findPluginIndexByName(name)
+		if found == -1 {
+			return merry.Errorf("plugin with name: '%s' not found", name)
+		}
+
+		plugs = plugs[found : found+1]
+	}
+
+	var failed []string
+
+	for _, p := range plugs {
+		if !m.diagnosePlugin(ctx, p.Loaded()) {
+			failed = append(failed, p.Name)
+		}
+	}
+
+	if len(failed) != 0 {
+		return merry.Errorf("found problems with plugins: %s", strings.Join(failed, ", "))
+	}
+
+	m.log.Successf("No problems found with %d plugins.\n", len(plugs))
+
+	return nil
+}
+
+func (m *PluginManager) diagnosePlugin(ctx context.Context, plug *plugins.Plugin) bool {
+	ok := true
+
+	check := func(title string, err error) {
+		if err != nil {
+			ok = false
+
+			m.log.Printf("  %s %s: %s\n", pterm.Red("\u2717"), title, err)
+
+			return
+		}
+
+		m.log.Printf("  %s %s\n", pterm.Green("\u2713"), title)
+	}
+
+	m.log.Section().Printf("Plugin '%s' %s", plug.Name, plug.Version)
+	m.log.Printf("  %s %s\n", pterm.Gray("dir"), plug.Dir)
+
+	bin, err := plug.Executable()
+	check(fmt.Sprintf("executable for %s: %s", plugins.CurrentArch(), bin), err)
+
+	if err != nil {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, client.DefaultTimeout)
+	defer cancel()
+
+	diag := plug.Client().Diagnose(ctx)
+
+	check("handshake", diag.Handshake)
+
+	if diag.Handshake == nil {
+		check("init", diag.Init)
+		check("gRPC services reachable", diag.Ping)
+	}
+
+	if diag.Handshake == nil && diag.Ping == nil {
+		data := [][]string{
+			{"Action", "Declared", "Implemented"},
+		}
+
+		for _, action := range plugins.ActionNames {
+			declared := slices.Contains(plug.Actions, action)
+			implemented := diag.Services[plugins.ActionService(action)]
+
+			if declared && !implemented {
+				ok = false
+			}
+
+			if !declared && !implemented {
+				continue
+			}
+
+			data = append(data, []string{pterm.Yellow(action), checkMark(declared), checkMark(implemented)})
+		}
+
+		if declared, implemented := len(plug.Commands) != 0, diag.Services[apiv1.CommandPluginService_ServiceDesc.ServiceName]; declared && !implemented {
+			ok = false
+
+			data = append(data, []string{pterm.Yellow("commands"), checkMark(declared), checkMark(implemented)})
+		}
+
+		_ = m.log.Table().WithHasHeader().WithData(pterm.TableData(data)).Render()
+	}
+
+	if len(plug.Supports) != 0 {
+		m.log.Printf("  %s %s\n", pterm.Gray("app types"), strings.Join(plug.Supports, ", "))
+	}
+
+	if len(plug.SupportedTypes) != 0 {
+		types := make([]string, len(plug.SupportedTypes))
+
+		for i, t := range plug.SupportedTypes {
+			types[i] = t.Type
+
+			if t.Match != nil && t.Match.Deploy != "" {
+				types[i] += fmt.Sprintf(" (deploy: %s)", t.Match.Deploy)
+			}
+		}
+
+		m.log.Printf("  %s %s\n", pterm.Gray("dependency types"), strings.Join(types, ", "))
+	}
+
+	if len(plug.Commands) != 0 {
+		m.log.Printf("  %s\n", pterm.Gray("commands"))
+
+		cmds := make([]string, 0, len(plug.Commands))
+		for c := range plug.Commands {
+			cmds = append(cmds, c)
+		}
+
+		sort.Strings(cmds)
+
+		for _, c := range cmds {
+			m.log.Printf("    %s %s\n", pterm.Yellow(c), plug.Commands[c].Short)
+		}
+	}
+
+	if diag.Stderr != "" {
+		m.log.Printf("  %s\n", pterm.Gray("stderr"))
+
+		for _, line := range strings.Split(diag.Stderr, "\n") {
+			m.log.Printf("    %s\n", line)
+		}
+	}
+
+	return ok
+}
+
+func checkMark(b bool) string {
+	if b {
+		return pterm.Green("\u2713")
+	}
+
+	return pterm.Red("\u2717")
+}
+
 func (m *PluginManager) List(ctx context.Context) error {
 	prog, _ := m.log.ProgressBar().WithTotal(len(m.cfg.Plugins)).WithTitle("Checking for plugin updates...").Start()
 
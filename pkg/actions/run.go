@@ -4,15 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
 	"os"
 	"os/exec"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ansel1/merry/v2"
@@ -24,11 +27,11 @@ import (
 	"github.com/outblocks/outblocks-cli/pkg/config"
 	"github.com/outblocks/outblocks-cli/pkg/logger"
 	"github.com/outblocks/outblocks-cli/pkg/plugins"
+	"github.com/outblocks/outblocks-cli/pkg/plugins/client"
 	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
 	"github.com/outblocks/outblocks-plugin-go/types"
 	plugin_util "github.com/outblocks/outblocks-plugin-go/util"
 	"github.com/outblocks/outblocks-plugin-go/util/errgroup"
-	"github.com/pterm/pterm"
 	"github.com/txn2/txeh"
 )
 
@@ -39,8 +42,13 @@ type Run struct {
 	cfg  *config.Project
 	opts *RunOptions
 
-	hosts      *txeh.Hosts
-	addedHosts []string
+	hosts       *txeh.Hosts
+	addedHosts  []string
+	output      *runOutput
+	ready       *run.Readiness
+	pinnedPorts map[string]int
+	dash        *runDashboard
+	requestLog  atomic.Bool
 }
 
 type RunOptions struct {
@@ -49,6 +57,13 @@ type RunOptions struct {
 	ListenPort     int
 	HostsSuffix    string
 	HostsRouting   bool
+	StaticRebuild  bool
+	UI             bool
+	RemoteEnv      string
+	RemoteState    *config.State
+	LogDir         string
+	LogTimestamps  bool
+	OnlyLogs       *util.TargetMatcher
 	Targets, Skips *util.TargetMatcher
 }
 
@@ -62,6 +77,12 @@ type runInfo struct {
 	localDeps     []*run.LocalDependency
 	pluginAppsMap map[*plugins.Plugin]*apiv1.RunRequest
 	pluginDepsMap map[*plugins.Plugin]*apiv1.RunRequest
+
+	// Start graph, maps app ID to IDs of apps/dependencies it waits for.
+	dependsOn map[string][]string
+
+	// Apps deployed in remote env that are not run locally.
+	remoteApps []*apiv1.AppState
 }
 
 const (
@@ -83,9 +104,10 @@ func (e *ErrExit) Error() string {
 
 func NewRun(log logger.Logger, cfg *config.Project, opts *RunOptions) *Run {
 	return &Run{
-		log:  log,
-		cfg:  cfg,
-		opts: opts,
+		log:   log,
+		cfg:   cfg,
+		opts:  opts,
+		ready: run.NewReadiness(),
 	}
 }
 
@@ -163,6 +185,36 @@ func grabPort(ports map[int]struct{}, lastPort int) int {
 	}
 }
 
+// reservePinnedPorts reserves pinned ports of apps/dependencies that have no run.port set, returning reserved ports by ID.
+// Pinned port that is already taken is skipped so that a new one gets allocated instead.
+func (d *Run) reservePinnedPorts(ports map[int]struct{}, n int, get func(i int) (id string, port int)) map[string]int {
+	reserved := make(map[string]int)
+
+	for i := 0; i < n; i++ {
+		id, port := get(i)
+		if port != 0 {
+			continue
+		}
+
+		pinned, ok := d.pinnedPorts[id]
+		if !ok {
+			continue
+		}
+
+		if !isPortFree(ports, pinned) {
+			d.log.Warnf("Pinned port %d of '%s' is already in use, allocating a new one.\n", pinned, id)
+
+			continue
+		}
+
+		grabPort(ports, pinned)
+
+		reserved[id] = pinned
+	}
+
+	return reserved
+}
+
 func (d *Run) prepareRunApps(info *runInfo, cfg *config.Project, ports map[int]struct{}, port int, hosts map[string]string) error {
 	var apps []config.App
 
@@ -187,14 +239,24 @@ func (d *Run) prepareRunApps(info *runInfo, cfg *config.Project, ports map[int]s
 		}
 	}
 
+	appPorts := d.reservePinnedPorts(ports, len(apps), func(i int) (string, int) {
+		return apps[i].ID(), apps[i].RunInfo().Port
+	})
+
 	for _, app := range apps {
 		runInfo := app.RunInfo()
 
-		if runInfo.Plugin == config.RunPluginDirect && runInfo.Command.IsEmpty() {
+		_, isStatic := app.(*config.StaticApp)
+
+		if runInfo.Plugin == config.RunPluginDirect && runInfo.Command.IsEmpty() && !isStatic {
 			return app.YAMLError("$.run.command", "run.command is required to run app")
 		}
 
 		appPort := runInfo.Port
+		if appPort == 0 {
+			appPort = appPorts[app.ID()]
+		}
+
 		if appPort == 0 {
 			appPort = grabPort(ports, port)
 		}
@@ -222,10 +284,17 @@ func (d *Run) prepareRunApps(info *runInfo, cfg *config.Project, ports map[int]s
 		}
 
 		if (d.opts.Direct || runInfo.Plugin == config.RunPluginDirect) && app.SupportsLocal() {
-			info.localApps = append(info.localApps, &run.LocalApp{
+			localApp := &run.LocalApp{
 				AppRun:  appRun,
 				Command: app.RunInfo().Command,
-			})
+			}
+
+			// Static apps without dev server command are served directly from their build dir.
+			if isStatic && runInfo.Command.IsEmpty() {
+				localApp.Static = d.localStaticApp(app.(*config.StaticApp))
+			}
+
+			info.localApps = append(info.localApps, localApp)
 
 			continue
 		}
@@ -247,6 +316,22 @@ func (d *Run) prepareRunApps(info *runInfo, cfg *config.Project, ports map[int]s
 	return nil
 }
 
+func (d *Run) localStaticApp(app *config.StaticApp) *run.LocalStaticApp {
+	removeTrailingSlash := app.Routing == config.StaticAppRoutingGatsby
+	if app.RemoveTrailingSlash != nil {
+		removeTrailingSlash = *app.RemoveTrailingSlash
+	}
+
+	return &run.LocalStaticApp{
+		BuildDir:            app.Build.Dir,
+		Routing:             app.Routing,
+		RemoveTrailingSlash: removeTrailingSlash,
+		BuildCommand:        app.Build.Command,
+		BuildEnv:            plugin_util.MergeStringMaps(app.Env(), app.Build.Env),
+		Rebuild:             d.opts.StaticRebuild,
+	}
+}
+
 func (d *Run) prepareRunDependencies(info *runInfo, cfg *config.Project, ports map[int]struct{}, port int, hosts map[string]string) error {
 	var deps []*config.Dependency
 
@@ -279,10 +364,18 @@ func (d *Run) prepareRunDependencies(info *runInfo, cfg *config.Project, ports m
 		}
 	}
 
+	depPorts := d.reservePinnedPorts(ports, len(deps), func(i int) (string, int) {
+		return deps[i].ID(), deps[i].Run.Port
+	})
+
 	for _, dep := range deps {
 		depType := dep.Proto()
 		depPort := dep.Run.Port
 
+		if depPort == 0 {
+			depPort = depPorts[dep.ID()]
+		}
+
 		if depPort == 0 {
 			depPort = grabPort(ports, port)
 		}
@@ -322,6 +415,71 @@ func (d *Run) prepareRunDependencies(info *runInfo, cfg *config.Project, ports m
 	return nil
 }
 
+func (d *Run) prepareStartGraph(info *runInfo, cfg *config.Project) error {
+	info.dependsOn = make(map[string][]string)
+
+	running := make([]string, 0, len(info.apps)+len(info.deps))
+
+	for _, app := range info.apps {
+		running = append(running, app.App.Id)
+	}
+
+	for _, dep := range info.deps {
+		running = append(running, config.ComputeDependencyID(dep.Dependency.Name))
+	}
+
+	localApps := make(map[string]*run.LocalApp, len(info.localApps))
+
+	for _, app := range info.localApps {
+		localApps[app.App.Id] = app
+	}
+
+	for _, appRun := range info.apps {
+		app := cfg.AppByID(appRun.App.Id)
+		if len(app.RunInfo().DependsOn) == 0 {
+			continue
+		}
+
+		matcher := util.NewTargetMatcher()
+
+		for _, t := range app.RunInfo().DependsOn {
+			_ = matcher.Add(t)
+		}
+
+		var prereqs []string
+
+		for _, id := range running {
+			if id != app.ID() && matcher.Matches(id) {
+				prereqs = append(prereqs, id)
+			}
+		}
+
+		for _, m := range matcher.Unmatched() {
+			d.log.Warnf("%s app '%s' depends on '%s' which is not running, ignoring.\n", util.Title(app.Type()), app.Name(), m.Input())
+		}
+
+		if len(prereqs) == 0 {
+			continue
+		}
+
+		localApp, ok := localApps[app.ID()]
+		if !ok {
+			d.log.Warnf("%s app '%s' is run through plugin, run.depends_on is only honored for apps run directly.\n", util.Title(app.Type()), app.Name())
+
+			continue
+		}
+
+		localApp.DependsOn = prereqs
+		info.dependsOn[app.ID()] = prereqs
+	}
+
+	if cycle := run.FindCycle(info.dependsOn); cycle != nil {
+		return merry.Errorf("run.depends_on cycle detected: %s", strings.Join(cycle, " -> "))
+	}
+
+	return nil
+}
+
 func (d *Run) prepareRun(cfg *config.Project) (*runInfo, error) {
 	info := &runInfo{
 		pluginAppsMap: make(map[*plugins.Plugin]*apiv1.RunRequest),
@@ -336,8 +494,15 @@ func (d *Run) prepareRun(cfg *config.Project) (*runInfo, error) {
 	port := d.opts.ListenPort + 1
 	ports := make(map[int]struct{})
 
+	var err error
+
+	d.pinnedPorts, err = loadPinnedPorts(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	// Apps.
-	err := d.prepareRunApps(info, cfg, ports, port, hosts)
+	err = d.prepareRunApps(info, cfg, ports, port, hosts)
 	if err != nil {
 		return nil, err
 	}
@@ -348,6 +513,12 @@ func (d *Run) prepareRun(cfg *config.Project) (*runInfo, error) {
 		return nil, err
 	}
 
+	// Start order.
+	err = d.prepareStartGraph(info, cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	// Gather hosts.
 	for _, app := range info.apps {
 		host, _ := urlutil.ExtractHostname(app.Url)
@@ -366,13 +537,126 @@ func (d *Run) newHTTPServer(routing map[*url.URL]*url.URL) *http.Server {
 	mux := http.NewServeMux()
 
 	for k, v := range routing {
-		mux.HandleFunc(k.Hostname()+k.Path, httputil.NewSingleHostReverseProxy(v).ServeHTTP)
+		proxy := httputil.NewSingleHostReverseProxy(v)
+
+		// Remote apps need a proper Host header to be routed correctly.
+		// Local path is replaced with remote one as apps may be deployed under a different path.
+		if v.Hostname() != loopbackIP {
+			target := v
+			prefix := strings.TrimSuffix(k.Path, "/")
+			director := proxy.Director
+			proxy.Director = func(req *http.Request) {
+				req.URL.Path = strings.TrimPrefix(req.URL.Path, prefix)
+				req.URL.RawPath = ""
+
+				director(req)
+				req.Host = target.Host
+			}
+		}
+
+		mux.HandleFunc(k.Hostname()+k.Path, proxy.ServeHTTP)
 	}
 
 	return &http.Server{
 		Addr:    fmt.Sprintf("%s:%d", d.opts.ListenIP, d.opts.ListenPort),
-		Handler: mux,
+		Handler: d.logRequests(mux),
+	}
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// logRequests reports requests going through local server to dashboard when request logging is toggled on.
+func (d *Run) logRequests(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if d.dash == nil || !d.requestLog.Load() {
+			h.ServeHTTP(w, req)
+
+			return
+		}
+
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		h.ServeHTTP(rec, req)
+
+		d.dash.request(fmt.Sprintf("%s %s%s %d %s", req.Method, req.Host, req.URL.RequestURI(), rec.status, time.Since(start).Round(time.Millisecond)))
+	})
+}
+
+// pluginDepVars returns vars of dependencies run through plugins keyed by dependency name.
+func (d *Run) pluginDepVars(pluginRets []*run.PluginRunResult) map[string]interface{} {
+	depVars := make(map[string]interface{})
+
+	for _, ret := range pluginRets {
+		for _, i := range ret.Info {
+			for id, vars := range i.Response.Vars {
+				m := make(map[string]interface{}, len(vars.Vars))
+				for k, v := range vars.Vars {
+					m[k] = v
+				}
+
+				depVars[d.cfg.DependencyByID(id).Name] = m
+			}
+		}
+	}
+
+	return depVars
+}
+
+// runAppVars returns vars of all apps run locally, falling back to apps deployed in remote env.
+func runAppVars(runInfo *runInfo) types.AppVars {
+	appVars := types.AppVarsFromAppRun(runInfo.apps)
+
+	if len(runInfo.remoteApps) > 0 {
+		appVars = types.MergeAppVars(types.AppVarsFromAppStates(runInfo.remoteApps), appVars)
+	}
+
+	return appVars
+}
+
+// localDepVars overrides dependency vars with their "local:" counterparts meant for apps run directly.
+func localDepVars(depVars map[string]interface{}) {
+	for _, m := range depVars {
+		vars := m.(map[string]interface{})
+
+		for k, v := range vars {
+			if strings.HasPrefix(k, "local:") {
+				vars[k[len("local:"):]] = v
+			}
+		}
+	}
+}
+
+func expandLocalAppEnv(app *run.LocalApp, eval *util.VarEvaluator) error {
+	var err error
+
+	app.App.Env, err = eval.ExpandStringMap(app.App.Env)
+	if err != nil {
+		return err
+	}
+
+	if app.Static != nil {
+		app.Static.BuildEnv, err = eval.ExpandStringMap(app.Static.BuildEnv)
+		if err != nil {
+			return err
+		}
 	}
+
+	return nil
 }
 
 func (d *Run) runAll(ctx context.Context, runInfo *runInfo) ([]*run.PluginRunResult, []*run.LocalRunResult, error) {
@@ -402,7 +686,7 @@ func (d *Run) runAll(ctx context.Context, runInfo *runInfo) ([]*run.PluginRunRes
 
 	// Process local dependencies.
 	if len(runInfo.localDeps) > 0 {
-		localRet, err := run.Local(ctx, nil, runInfo.localDeps)
+		localRet, err := run.Local(ctx, nil, runInfo.localDeps, d.ready)
 		if err != nil {
 			spinner.Stop()
 			return nil, nil, err
@@ -418,25 +702,17 @@ func (d *Run) runAll(ctx context.Context, runInfo *runInfo) ([]*run.PluginRunRes
 	}
 
 	// Process env vars.
-	depVars := make(map[string]interface{})
-
-	for _, ret := range pluginRets {
-		for _, i := range ret.Info {
-			for id, vars := range i.Response.Vars {
-				m := make(map[string]interface{}, len(vars.Vars))
-				for k, v := range vars.Vars {
-					m[k] = v
-				}
+	depVars := d.pluginDepVars(pluginRets)
+	appVars := runAppVars(runInfo)
 
-				depVars[d.cfg.DependencyByID(id).Name] = m
-			}
+	if len(depVars) > 0 {
+		if err := d.saveRunDepVars(runInfo, depVars); err != nil {
+			d.log.Warnf("Cannot record dependency vars: %s\n", err)
 		}
 	}
 
 	var err error
 
-	appVars := types.AppVarsFromAppRun(runInfo.apps)
-
 	for _, app := range runInfo.pluginApps {
 		eval := util.NewVarEvaluator(types.VarsForApp(appVars, app.App, depVars))
 
@@ -446,20 +722,10 @@ func (d *Run) runAll(ctx context.Context, runInfo *runInfo) ([]*run.PluginRunRes
 		}
 	}
 
-	for _, app := range runInfo.localApps {
-		for _, m := range depVars {
-			vars := m.(map[string]interface{})
-
-			for k, v := range vars {
-				if strings.HasPrefix(k, "local:") {
-					vars[k[len("local:"):]] = v
-				}
-			}
-		}
-
-		eval := util.NewVarEvaluator(types.VarsForApp(appVars, app.App, depVars))
+	localDepVars(depVars)
 
-		app.App.Env, err = eval.ExpandStringMap(app.App.Env)
+	for _, app := range runInfo.localApps {
+		err = expandLocalAppEnv(app, util.NewVarEvaluator(types.VarsForApp(appVars, app.App, depVars)))
 		if err != nil {
 			return nil, nil, err
 		}
@@ -484,7 +750,7 @@ func (d *Run) runAll(ctx context.Context, runInfo *runInfo) ([]*run.PluginRunRes
 
 	// Process local apps.
 	if len(runInfo.localApps) > 0 {
-		localRet, err := run.Local(ctx, runInfo.localApps, nil)
+		localRet, err := run.Local(ctx, runInfo.localApps, nil, d.ready)
 		if err != nil {
 			spinner.Stop()
 			return nil, nil, err
@@ -505,10 +771,45 @@ func (d *Run) runAll(ctx context.Context, runInfo *runInfo) ([]*run.PluginRunRes
 }
 
 func (d *Run) waitAll(ctx context.Context, runInfo *runInfo) error {
-	prog, _ := d.log.ProgressBar().WithTotal(len(runInfo.apps)).WithTitle("Waiting for apps and dependencies to be up...").Start()
+	// Dependencies are health checked only when some app waits for them.
+	awaited := make(map[string]struct{})
 
-	httpClient := &http.Client{
-		Timeout: healthcheckTimeout,
+	for _, ids := range runInfo.dependsOn {
+		for _, id := range ids {
+			awaited[id] = struct{}{}
+		}
+	}
+
+	var deps []*apiv1.DependencyRun
+
+	for _, dep := range runInfo.deps {
+		if _, ok := awaited[config.ComputeDependencyID(dep.Dependency.Name)]; ok {
+			deps = append(deps, dep)
+		}
+	}
+
+	// Dashboard shows state of every dependency.
+	if d.dash != nil {
+		deps = runInfo.deps
+	}
+
+	var prog logger.Progressbar
+
+	if d.dash == nil {
+		prog, _ = d.log.ProgressBar().WithTotal(len(runInfo.apps) + len(deps)).WithTitle("Waiting for apps and dependencies to be up...").Start()
+	}
+
+	up := func(id, msg string) {
+		d.ready.MarkReady(id)
+
+		if d.dash != nil {
+			d.dash.setState(id, dashboardStateReady)
+
+			return
+		}
+
+		d.log.Println(msg)
+		prog.Increment()
 	}
 
 	g, _ := errgroup.WithContext(ctx)
@@ -516,73 +817,103 @@ func (d *Run) waitAll(ctx context.Context, runInfo *runInfo) error {
 	for _, app := range runInfo.apps {
 		app := app
 
-		req, err := http.NewRequestWithContext(ctx, "HEAD", fmt.Sprintf("http://%s:%d/", app.Ip, app.Port), http.NoBody)
-		if err != nil {
-			return err
-		}
-
 		g.Go(func() error {
-			for {
-				resp, err := httpClient.Do(req)
-				if errors.Is(err, context.Canceled) {
-					return err
-				}
+			err := d.waitApp(ctx, app)
+			if err != nil {
+				return err
+			}
 
-				if err == nil {
-					_ = resp.Body.Close()
+			up(app.App.Id, fmt.Sprintf("%s App '%s' is UP.", util.Title(app.App.Type), app.App.Name))
 
-					d.log.Printf("%s App '%s' is UP.\n", util.Title(app.App.Type), app.App.Name)
-					prog.Increment()
+			return nil
+		})
+	}
 
-					return nil
-				}
+	for _, dep := range deps {
+		dep := dep
 
-				time.Sleep(healthcheckSleep)
+		g.Go(func() error {
+			err := waitDependency(ctx, dep)
+			if err != nil {
+				return err
 			}
+
+			up(config.ComputeDependencyID(dep.Dependency.Name), fmt.Sprintf("Dependency '%s' is UP.", dep.Dependency.Name))
+
+			return nil
 		})
 	}
 
 	err := g.Wait()
 
-	prog.Stop()
+	if prog != nil {
+		prog.Stop()
+	}
 
 	return err
 }
 
-func formatRunOutput(log logger.Logger, cfg *config.Project, r *apiv1.RunOutputResponse) {
-	var prefix string
+// waitDependency blocks until dependency accepts connections.
+func waitDependency(ctx context.Context, dep *apiv1.DependencyRun) error {
+	dialer := &net.Dialer{
+		Timeout: healthcheckTimeout,
+	}
+
+	for {
+		conn, err := dialer.DialContext(ctx, "tcp", fmt.Sprintf("%s:%d", dep.Ip, dep.Port))
+		if errors.Is(err, context.Canceled) {
+			return err
+		}
+
+		if err == nil {
+			_ = conn.Close()
 
-	msg := plugin_util.StripAnsiControl(r.Message)
+			return nil
+		}
 
-	switch r.Source {
-	case apiv1.RunOutputResponse_SOURCE_APP:
-		app := cfg.AppByID(r.Id)
-		prefix = fmt.Sprintf("APP:%s:%s:", app.Type(), app.Name())
+		time.Sleep(healthcheckSleep)
+	}
+}
 
-	case apiv1.RunOutputResponse_SOURCE_DEPENDENCY:
-		prefix = fmt.Sprintf("DEP:%s", r.Name)
+// waitApp blocks until app responds to http requests.
+func (d *Run) waitApp(ctx context.Context, app *apiv1.AppRun) error {
+	httpClient := &http.Client{
+		Timeout: healthcheckTimeout,
+	}
 
-	case apiv1.RunOutputResponse_SOURCE_UNSPECIFIED:
-		prefix = fmt.Sprintf("UNKNOWN:%s", r.Name)
+	req, err := http.NewRequestWithContext(ctx, "HEAD", fmt.Sprintf("http://%s:%d/", app.Ip, app.Port), http.NoBody)
+	if err != nil {
+		return err
 	}
 
-	if r.Stream == apiv1.RunOutputResponse_STREAM_STDERR {
-		log.Printf("%s %s\n", pterm.FgRed.Sprint(prefix), msg)
-	} else {
-		log.Printf("%s %s\n", pterm.FgGreen.Sprint(prefix), msg)
+	for {
+		resp, err := httpClient.Do(req)
+		if errors.Is(err, context.Canceled) {
+			return err
+		}
+
+		if err == nil {
+			_ = resp.Body.Close()
+
+			return nil
+		}
+
+		time.Sleep(healthcheckSleep)
 	}
 }
 
-func (d *Run) addAllHosts(runInfo *runInfo) (map[*url.URL]*url.URL, error) {
-	hosts := map[string]struct{}{
+// hostsRouting returns hosts to add and routing of local urls to locally run apps and apps deployed in remote env.
+// Remote apps are routed by their configured url so that they are reachable at the same address as if they were run locally.
+func (d *Run) hostsRouting(runInfo *runInfo) (hosts []string, routing map[*url.URL]*url.URL) {
+	hostsMap := map[string]struct{}{
 		d.loopbackHost(): {},
 	}
 
-	routing := make(map[*url.URL]*url.URL)
+	routing = make(map[*url.URL]*url.URL)
 
 	for _, s := range runInfo.apps {
 		u, _ := url.Parse(s.Url)
-		hosts[u.Hostname()] = struct{}{}
+		hostsMap[u.Hostname()] = struct{}{}
 
 		if u.Path == "" {
 			u.Path = "/"
@@ -595,13 +926,52 @@ func (d *Run) addAllHosts(runInfo *runInfo) (map[*url.URL]*url.URL, error) {
 		routing[u] = &uLocal
 	}
 
-	hostsList := make([]string, 0, len(hosts))
+	for _, s := range runInfo.remoteApps {
+		remote, err := url.Parse(remoteAppURL(s))
+		if err != nil || remote.Hostname() == "" {
+			d.log.Warnf("%s App '%s' deployed in env '%s' has no valid url and cannot be routed locally.\n", util.Title(s.App.Type), s.App.Name, d.opts.RemoteEnv)
+
+			continue
+		}
+
+		// Apps missing in local config (e.g. not loaded or removed) are routed by their remote url.
+		appURL := &url.URL{Host: remote.Hostname(), Path: remote.Path}
+		pathRedirect := remote.Path
+
+		if app := d.cfg.AppByID(s.App.Id); app != nil && app.URL() != nil {
+			appURL = app.URL()
+			pathRedirect = app.PathRedirect()
+		}
+
+		u, _ := url.Parse(d.localURL(appURL, d.opts.ListenPort, pathRedirect))
+		hostsMap[u.Hostname()] = struct{}{}
+
+		if u.Path == "" {
+			u.Path = "/"
+		}
 
-	for h := range hosts {
-		hostsList = append(hostsList, h)
+		routing[u] = &url.URL{
+			Scheme: remote.Scheme,
+			Host:   remote.Host,
+			Path:   remote.Path,
+		}
 	}
 
-	err := d.AddHosts(hostsList...)
+	hosts = make([]string, 0, len(hostsMap))
+
+	for h := range hostsMap {
+		hosts = append(hosts, h)
+	}
+
+	sort.Strings(hosts)
+
+	return hosts, routing
+}
+
+func (d *Run) addAllHosts(runInfo *runInfo) (map[*url.URL]*url.URL, error) {
+	hosts, routing := d.hostsRouting(runInfo)
+
+	err := d.AddHosts(hosts...)
 	if err != nil {
 		return nil, merry.New("are you running with sudo? or try running with hosts-routing disabled")
 	}
@@ -666,10 +1036,25 @@ func (d *Run) start(ctx context.Context, runInfo *runInfo) (*sync.WaitGroup, err
 					return
 				}
 
-				formatRunOutput(d.log, d.cfg, msg)
+				d.output.Handle(msg)
 			}
 		}()
 
+		// With dashboard, apps that exit are shown as crashed and can be restarted instead of stopping everything.
+		if d.dash != nil {
+			d.dash.supervise(runnerCtx, localRet)
+
+			go func() {
+				<-runnerCtx.Done()
+
+				_ = localRet.Stop()
+
+				wg.Done()
+			}()
+
+			continue
+		}
+
 		go func() {
 			err = localRet.Wait()
 			if err != nil {
@@ -691,7 +1076,7 @@ func (d *Run) start(ctx context.Context, runInfo *runInfo) (*sync.WaitGroup, err
 					return
 				}
 
-				formatRunOutput(d.log, d.cfg, msg)
+				d.output.Handle(msg)
 			}
 		}()
 
@@ -708,6 +1093,24 @@ func (d *Run) start(ctx context.Context, runInfo *runInfo) (*sync.WaitGroup, err
 		}()
 	}
 
+	if d.dash != nil {
+		go func() {
+			_ = d.waitAll(runnerCtx, runInfo)
+		}()
+
+		err = d.dash.run(runnerCtx)
+
+		runnerCancel()
+
+		select {
+		case err := <-errCh:
+			return &wg, err
+		default:
+		}
+
+		return &wg, err
+	}
+
 	// Healthcheck.
 	err = d.waitAll(runnerCtx, runInfo)
 	if err != nil {
@@ -728,6 +1131,10 @@ func (d *Run) start(ctx context.Context, runInfo *runInfo) (*sync.WaitGroup, err
 		d.log.Printf("%s App '%s' listening at %s\n", util.Title(a.App.Type), a.App.Name, a.Url)
 	}
 
+	for _, a := range runInfo.remoteApps {
+		d.log.Printf("%s App '%s' using remote env '%s' at %s\n", util.Title(a.App.Type), a.App.Name, d.opts.RemoteEnv, remoteAppURL(a))
+	}
+
 	d.log.Println()
 
 	<-runnerCtx.Done()
@@ -741,6 +1148,78 @@ func (d *Run) start(ctx context.Context, runInfo *runInfo) (*sync.WaitGroup, err
 	return &wg, nil
 }
 
+func remoteAppURL(a *apiv1.AppState) string {
+	switch {
+	case a.App.Url != "":
+		return a.App.Url
+	case a.Dns != nil && a.Dns.Url != "":
+		return a.Dns.Url
+	case a.Dns != nil:
+		return a.Dns.CloudUrl
+	}
+
+	return ""
+}
+
+// loadRemoteApps returns apps deployed in remote env that are not run locally.
+func (d *Run) loadRemoteApps(ctx context.Context, runInfo *runInfo) ([]*apiv1.AppState, error) {
+	yamlContext := &client.YAMLContext{
+		Prefix: "$.state",
+		Data:   d.cfg.YAMLData(),
+	}
+
+	state, _, err := getState(ctx, d.opts.RemoteState, false, 0, true, yamlContext)
+	if err != nil {
+		return nil, merry.Errorf("cannot read state of env '%s': %w", d.opts.RemoteEnv, err)
+	}
+
+	running := make(map[string]struct{}, len(runInfo.apps))
+
+	for _, a := range runInfo.apps {
+		running[a.App.Id] = struct{}{}
+	}
+
+	var ret []*apiv1.AppState
+
+	for id, a := range state.Apps {
+		if _, ok := running[id]; ok || a.App == nil {
+			continue
+		}
+
+		ret = append(ret, a)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].App.Id < ret[j].App.Id
+	})
+
+	if len(ret) == 0 {
+		d.log.Warnf("No deployed apps found in env '%s'.\n", d.opts.RemoteEnv)
+	}
+
+	return ret, nil
+}
+
+func (d *Run) checkOnlyLogs(runInfo *runInfo) {
+	if d.opts.OnlyLogs.IsEmpty() {
+		return
+	}
+
+	for _, a := range runInfo.apps {
+		d.opts.OnlyLogs.Matches(a.App.Id)
+	}
+
+	for _, dep := range runInfo.deps {
+		d.opts.OnlyLogs.Matches(config.ComputeDependencyID(dep.Dependency.Name))
+	}
+
+	for _, m := range d.opts.OnlyLogs.Unmatched() {
+		d.log.Warnf("Logs filter '%s' does not match any running app or dependency.\n", m.Input())
+	}
+
+	d.opts.OnlyLogs.ResetMatches()
+}
+
 func (d *Run) runSelfAsSudo() error {
 	args := []string{"-E"}
 
@@ -767,6 +1246,10 @@ func (d *Run) runSelfAsSudo() error {
 }
 
 func (d *Run) Run(ctx context.Context) error {
+	if d.opts.UI && !util.IsTerminal() {
+		return merry.New("dashboard requires an interactive terminal")
+	}
+
 	if d.opts.HostsRouting && runtime.GOOS != "windows" && os.Geteuid() > 0 {
 		return d.runSelfAsSudo()
 	}
@@ -781,6 +1264,27 @@ func (d *Run) Run(ctx context.Context) error {
 		return err
 	}
 
+	if d.opts.RemoteState != nil {
+		runInfo.remoteApps, err = d.loadRemoteApps(ctx, runInfo)
+		if err != nil {
+			return err
+		}
+	}
+
+	d.output, err = newRunOutput(d.log, d.cfg, d.opts)
+	if err != nil {
+		return err
+	}
+
+	defer d.output.Close()
+
+	if d.opts.UI {
+		d.dash = newRunDashboard(d, runInfo)
+		d.output.sink = d.dash.output
+	}
+
+	d.checkOnlyLogs(runInfo)
+
 	wg, err := d.start(ctx, runInfo)
 
 	cleanupErr := d.cleanup()
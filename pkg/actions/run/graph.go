@@ -0,0 +1,115 @@
+package run
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// Readiness tracks which apps and dependencies passed their health checks so that dependents can be started.
+type Readiness struct {
+	mu    sync.Mutex
+	ready map[string]chan struct{}
+}
+
+func NewReadiness() *Readiness {
+	return &Readiness{
+		ready: make(map[string]chan struct{}),
+	}
+}
+
+func (r *Readiness) ch(id string) chan struct{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.ready[id]
+	if !ok {
+		c = make(chan struct{})
+		r.ready[id] = c
+	}
+
+	return c
+}
+
+func (r *Readiness) MarkReady(id string) {
+	c := r.ch(id)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	select {
+	case <-c:
+	default:
+		close(c)
+	}
+}
+
+func (r *Readiness) Wait(ctx context.Context, ids []string) error {
+	for _, id := range ids {
+		select {
+		case <-r.ch(id):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// FindCycle returns first dependency cycle found in graph (as a path ending with its starting node) or nil if there is none.
+func FindCycle(graph map[string][]string) []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int, len(graph))
+	ids := make([]string, 0, len(graph))
+
+	for id := range graph {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	var (
+		path  []string
+		visit func(id string) []string
+	)
+
+	visit = func(id string) []string {
+		switch state[id] {
+		case visited:
+			return nil
+		case visiting:
+			for i, p := range path {
+				if p == id {
+					return append(append([]string{}, path[i:]...), id)
+				}
+			}
+		}
+
+		state[id] = visiting
+		path = append(path, id)
+
+		for _, dep := range graph[id] {
+			if cycle := visit(dep); cycle != nil {
+				return cycle
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[id] = visited
+
+		return nil
+	}
+
+	for _, id := range ids {
+		if cycle := visit(id); cycle != nil {
+			return cycle
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package run
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFindCycle(t *testing.T) {
+	t.Parallel()
+
+	noCycle := map[string][]string{
+		"app_service_web": {"app_service_api"},
+		"app_service_api": {"dep_database"},
+	}
+
+	if cycle := FindCycle(noCycle); cycle != nil {
+		t.Fatalf("unexpected cycle: %v", cycle)
+	}
+
+	withCycle := map[string][]string{
+		"app_service_a": {"app_service_b"},
+		"app_service_b": {"app_service_c"},
+		"app_service_c": {"app_service_a"},
+	}
+
+	want := []string{"app_service_a", "app_service_b", "app_service_c", "app_service_a"}
+	if cycle := FindCycle(withCycle); !reflect.DeepEqual(cycle, want) {
+		t.Fatalf("unexpected cycle: got %v want %v", cycle, want)
+	}
+}
+
+func TestReadinessWait(t *testing.T) {
+	t.Parallel()
+
+	r := NewReadiness()
+
+	go func() {
+		r.MarkReady("dep_database")
+		r.MarkReady("app_service_api")
+		r.MarkReady("app_service_api")
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := r.Wait(ctx, []string{"app_service_api", "dep_database"}); err != nil {
+		t.Fatalf("Wait returned error: %v", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := r.Wait(ctx, []string{"app_service_web"}); err == nil {
+		t.Fatal("Wait should fail for app that never becomes ready")
+	}
+}
@@ -2,6 +2,7 @@ package run
 
 import (
 	"context"
+	"sync"
 
 	"github.com/ansel1/merry/v2"
 	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
@@ -11,11 +12,24 @@ type LocalRunResult struct {
 	Apps     map[string]*LocalAppRunInfo
 	Deps     map[string]*LocalDependencyRunInfo
 	OutputCh chan *apiv1.RunOutputResponse
+
+	cancel  context.CancelFunc
+	mu      sync.Mutex
+	stopped bool
 }
 
 func (l *LocalRunResult) Stop() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.stopped {
+		return nil
+	}
+
 	var firstErr error
 
+	l.cancel()
+
 	for _, a := range l.Apps {
 		err := a.Stop()
 		if err != nil && firstErr == nil {
@@ -23,16 +37,79 @@ func (l *LocalRunResult) Stop() error {
 		}
 	}
 
+	l.stopped = true
+
 	close(l.OutputCh)
 
 	return firstErr
 }
 
+// App returns current run info of app with given id, it changes after every restart.
+func (l *LocalRunResult) App(id string) *LocalAppRunInfo {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.Apps[id]
+}
+
+// Restart stops app with given id and starts it again with the same config.
+func (l *LocalRunResult) Restart(id string) (*LocalAppRunInfo, error) {
+	l.mu.Lock()
+
+	if l.stopped {
+		l.mu.Unlock()
+
+		return nil, merry.New("already stopped")
+	}
+
+	old, ok := l.Apps[id]
+
+	l.mu.Unlock()
+
+	if !ok {
+		return nil, merry.Errorf("app with id '%s' is not run locally", id)
+	}
+
+	// App may have already exited on its own, nothing to report then.
+	// Stopping waits for app to exit, it cannot block other calls.
+	_ = old.Stop()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.stopped {
+		return nil, merry.New("already stopped")
+	}
+
+	if l.Apps[id] != old {
+		return nil, merry.Errorf("app with id '%s' is already being restarted", id)
+	}
+
+	info, err := NewLocalAppRunInfo(old.LocalApp)
+	if err != nil {
+		return nil, err
+	}
+
+	l.Apps[id] = info
+
+	return info, info.Run(l.OutputCh)
+}
+
 func (l *LocalRunResult) Wait() error {
-	errCh := make(chan error, 1)
-	total := len(l.Apps)
+	l.mu.Lock()
+
+	apps := make([]*LocalAppRunInfo, 0, len(l.Apps))
 
 	for _, a := range l.Apps {
+		apps = append(apps, a)
+	}
+
+	l.mu.Unlock()
+
+	errCh := make(chan error, 1)
+	total := len(apps)
+
+	for _, a := range apps {
 		a := a
 
 		go func() {
@@ -59,20 +136,37 @@ func (l *LocalRunResult) Wait() error {
 	return err
 }
 
-func Local(ctx context.Context, localApps []*LocalApp, localDeps []*LocalDependency) (*LocalRunResult, error) {
+// Local runs apps and dependencies locally. Apps with prerequisites are started only after they are marked as ready.
+func Local(ctx context.Context, localApps []*LocalApp, localDeps []*LocalDependency, ready *Readiness) (*LocalRunResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+
 	ret := &LocalRunResult{
 		Apps:     make(map[string]*LocalAppRunInfo),
 		Deps:     make(map[string]*LocalDependencyRunInfo),
 		OutputCh: make(chan *apiv1.RunOutputResponse),
+		cancel:   cancel,
 	}
 
 	for _, app := range localApps {
-		info, err := app.Run(ret.OutputCh)
+		info, err := NewLocalAppRunInfo(app)
 		if err != nil {
+			cancel()
 			return nil, err
 		}
 
 		ret.Apps[app.App.Id] = info
+
+		if len(app.DependsOn) != 0 {
+			go info.RunAfter(ctx, ready, ret.OutputCh)
+
+			continue
+		}
+
+		err = info.Run(ret.OutputCh)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
 	}
 
 	// for _, dep := range localDeps {
@@ -2,6 +2,7 @@ package run
 
 import (
 	"bufio"
+	"context"
 	"sync"
 	"time"
 
@@ -12,13 +13,20 @@ import (
 
 type LocalApp struct {
 	*apiv1.AppRun
-	Command *command.StringCommand
+	Command   *command.StringCommand
+	Static    *LocalStaticApp
+	DependsOn []string
 }
 
 type LocalAppRunInfo struct {
 	*command.Cmd
 	*LocalApp
-	wg sync.WaitGroup
+	static   *staticServer
+	wg       sync.WaitGroup
+	started  chan struct{}
+	startErr error
+	stopCh   chan struct{}
+	stopOnce sync.Once
 }
 
 const (
@@ -28,6 +36,14 @@ const (
 func NewLocalAppRunInfo(a *LocalApp) (*LocalAppRunInfo, error) {
 	info := &LocalAppRunInfo{
 		LocalApp: a,
+		started:  make(chan struct{}),
+		stopCh:   make(chan struct{}),
+	}
+
+	if a.Static != nil {
+		info.static = newStaticServer(a)
+
+		return info, nil
 	}
 
 	var err error
@@ -45,6 +61,14 @@ func NewLocalAppRunInfo(a *LocalApp) (*LocalAppRunInfo, error) {
 }
 
 func (a *LocalAppRunInfo) Run(outputCh chan<- *apiv1.RunOutputResponse) error {
+	defer close(a.started)
+
+	if a.static != nil {
+		a.startErr = a.static.Run(outputCh)
+
+		return a.startErr
+	}
+
 	a.wg.Add(2)
 
 	go func() {
@@ -81,15 +105,59 @@ func (a *LocalAppRunInfo) Run(outputCh chan<- *apiv1.RunOutputResponse) error {
 		a.wg.Done()
 	}()
 
-	err := a.Cmd.Run()
+	a.startErr = a.Cmd.Run()
+
+	return a.startErr
+}
+
+// RunAfter starts app after all of its prerequisites are ready.
+// Waiting is canceled when app is stopped in the meantime.
+func (a *LocalAppRunInfo) RunAfter(ctx context.Context, ready *Readiness, outputCh chan<- *apiv1.RunOutputResponse) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-a.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	err := ready.Wait(ctx, a.DependsOn)
+	if err == nil {
+		select {
+		case <-a.stopCh:
+			err = context.Canceled
+		default:
+		}
+	}
+
 	if err != nil {
-		return err
+		a.startErr = err
+		close(a.started)
+
+		return
 	}
 
-	return nil
+	_ = a.Run(outputCh)
 }
 
 func (a *LocalAppRunInfo) Stop() error {
+	a.stopOnce.Do(func() {
+		close(a.stopCh)
+	})
+
+	<-a.started
+
+	if a.startErr != nil {
+		return nil
+	}
+
+	if a.static != nil {
+		return a.static.Stop(localAppCleanupTimeout)
+	}
+
 	err := a.Cmd.Stop(localAppCleanupTimeout)
 
 	a.wg.Wait()
@@ -98,6 +166,16 @@ func (a *LocalAppRunInfo) Stop() error {
 }
 
 func (a *LocalAppRunInfo) Wait() error {
+	<-a.started
+
+	if a.startErr != nil {
+		return a.startErr
+	}
+
+	if a.static != nil {
+		return a.static.Wait()
+	}
+
 	err := a.Cmd.Wait()
 
 	a.wg.Wait()
@@ -0,0 +1,393 @@
+package run
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ansel1/merry/v2"
+	"github.com/fsnotify/fsnotify"
+	"github.com/outblocks/outblocks-cli/internal/util"
+	"github.com/outblocks/outblocks-cli/pkg/config"
+	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
+	"github.com/outblocks/outblocks-plugin-go/util/command"
+)
+
+const (
+	staticIndexFile        = "index.html"
+	staticNotFoundFile     = "404.html"
+	staticRebuildDebounce  = 500 * time.Millisecond
+	staticServerReadHeader = 10 * time.Second
+)
+
+var staticWatchSkipDirs = map[string]struct{}{
+	"node_modules": {},
+	".git":         {},
+	".cache":       {},
+	".outblocks":   {},
+}
+
+// LocalStaticApp describes a static app served directly from its build dir instead of a dev server command.
+type LocalStaticApp struct {
+	BuildDir            string
+	Routing             string
+	RemoveTrailingSlash bool
+	BuildCommand        *command.StringCommand
+	BuildEnv            map[string]string
+	Rebuild             bool
+}
+
+type staticServer struct {
+	app *LocalApp
+	srv *http.Server
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	outputCh chan<- *apiv1.RunOutputResponse
+	watcher  *fsnotify.Watcher
+	buildMu  sync.Mutex
+	wg       sync.WaitGroup
+	done     chan struct{}
+	err      error
+}
+
+func newStaticServer(a *LocalApp) *staticServer {
+	return &staticServer{
+		app:  a,
+		done: make(chan struct{}),
+	}
+}
+
+// NewStaticHandler returns http handler serving files from dir, mimicking how static apps are served when deployed.
+func NewStaticHandler(dir, routing string, removeTrailingSlash bool) http.Handler {
+	return &staticHandler{
+		dir:                 dir,
+		routing:             routing,
+		removeTrailingSlash: removeTrailingSlash,
+	}
+}
+
+type staticHandler struct {
+	dir                 string
+	routing             string
+	removeTrailingSlash bool
+}
+
+func (h *staticHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p := path.Clean("/" + r.URL.Path)
+
+	if h.removeTrailingSlash && p != "/" && strings.HasSuffix(r.URL.Path, "/") {
+		u := *r.URL
+		u.Path = p
+
+		http.Redirect(w, r, u.String(), http.StatusMovedPermanently)
+
+		return
+	}
+
+	if h.exists(p) {
+		h.serveFile(w, r, p, http.StatusOK)
+		return
+	}
+
+	switch {
+	case h.routing == config.StaticAppRoutingReact && h.exists("/"+staticIndexFile):
+		h.serveFile(w, r, "/"+staticIndexFile, http.StatusOK)
+	case h.exists("/" + staticNotFoundFile):
+		h.serveFile(w, r, "/"+staticNotFoundFile, http.StatusNotFound)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *staticHandler) exists(p string) bool {
+	fp := filepath.Join(h.dir, filepath.FromSlash(p))
+
+	st, err := os.Stat(fp)
+	if err != nil {
+		return false
+	}
+
+	if !st.IsDir() {
+		return true
+	}
+
+	_, err = os.Stat(filepath.Join(fp, staticIndexFile))
+
+	return err == nil
+}
+
+func (h *staticHandler) serveFile(w http.ResponseWriter, r *http.Request, p string, status int) {
+	fp := filepath.Join(h.dir, filepath.FromSlash(p))
+
+	if st, err := os.Stat(fp); err == nil && st.IsDir() {
+		fp = filepath.Join(fp, staticIndexFile)
+	}
+
+	f, err := os.Open(fp)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	defer f.Close()
+
+	st, err := f.Stat()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if status != http.StatusOK {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(status)
+
+		if r.Method != http.MethodHead {
+			_, _ = io.Copy(w, f)
+		}
+
+		return
+	}
+
+	http.ServeContent(w, r, st.Name(), st.ModTime(), f)
+}
+
+func (s *staticServer) buildDir() string {
+	return filepath.Join(s.app.App.Dir, s.app.Static.BuildDir)
+}
+
+func (s *staticServer) send(stream apiv1.RunOutputResponse_Stream, msg string) {
+	s.outputCh <- &apiv1.RunOutputResponse{
+		Source:  apiv1.RunOutputResponse_SOURCE_APP,
+		Stream:  stream,
+		Id:      s.app.App.Id,
+		Name:    s.app.App.Name,
+		Message: msg,
+	}
+}
+
+func (s *staticServer) build(ctx context.Context) error {
+	s.buildMu.Lock()
+	defer s.buildMu.Unlock()
+
+	cmd, err := command.New(
+		s.app.Static.BuildCommand.ExecCmdAsUser(),
+		command.WithDir(s.app.App.Dir),
+		command.WithEnv(util.FlattenEnvMap(s.app.Static.BuildEnv)),
+	)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+
+	wg.Add(2)
+
+	go func() {
+		sc := bufio.NewScanner(cmd.Stdout())
+		for sc.Scan() {
+			s.send(apiv1.RunOutputResponse_STREAM_STDOUT, sc.Text())
+		}
+
+		wg.Done()
+	}()
+
+	go func() {
+		sc := bufio.NewScanner(cmd.Stderr())
+		for sc.Scan() {
+			s.send(apiv1.RunOutputResponse_STREAM_STDERR, sc.Text())
+		}
+
+		wg.Done()
+	}()
+
+	err = cmd.Run()
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		_ = cmd.Stop(localAppCleanupTimeout)
+	case <-cmd.WaitChannel():
+	}
+
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return cmd.Wait()
+}
+
+func (s *staticServer) Run(outputCh chan<- *apiv1.RunOutputResponse) error {
+	s.outputCh = outputCh
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+
+	s.srv = &http.Server{
+		Addr:              fmt.Sprintf("%s:%d", s.app.Ip, s.app.Port),
+		Handler:           NewStaticHandler(s.buildDir(), s.app.Static.Routing, s.app.Static.RemoveTrailingSlash),
+		ReadHeaderTimeout: staticServerReadHeader,
+	}
+
+	hasBuild := !s.app.Static.BuildCommand.IsEmpty()
+	_, statErr := os.Stat(s.buildDir())
+
+	if os.IsNotExist(statErr) && !hasBuild {
+		return merry.Errorf("app %s build dir '%s' does not exist and no build command is defined", s.app.App.Name, s.buildDir())
+	}
+
+	s.wg.Add(1)
+
+	go func() {
+		defer s.wg.Done()
+		defer close(s.done)
+
+		// Build first if there is nothing to serve yet or when rebuilding is requested anyway.
+		if hasBuild && (os.IsNotExist(statErr) || s.app.Static.Rebuild) {
+			if err := s.build(s.ctx); err != nil {
+				if s.ctx.Err() == nil {
+					s.err = merry.Errorf("build failed: %w", err)
+				}
+
+				return
+			}
+		}
+
+		if s.app.Static.Rebuild && hasBuild {
+			if err := s.watch(); err != nil {
+				s.err = merry.Errorf("cannot watch for changes: %w", err)
+
+				return
+			}
+		}
+
+		l, err := net.Listen("tcp", s.srv.Addr)
+		if err != nil {
+			s.err = err
+
+			return
+		}
+
+		err = s.srv.Serve(l)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.err = merry.Errorf("static server: %w", err)
+		}
+	}()
+
+	return nil
+}
+
+func (s *staticServer) watch() error {
+	var err error
+
+	s.watcher, err = fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	buildDir, _ := filepath.Abs(s.buildDir())
+
+	err = filepath.WalkDir(s.app.App.Dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return err
+		}
+
+		if _, ok := staticWatchSkipDirs[d.Name()]; ok {
+			return filepath.SkipDir
+		}
+
+		if abs, _ := filepath.Abs(p); abs == buildDir {
+			return filepath.SkipDir
+		}
+
+		return s.watcher.Add(p)
+	})
+	if err != nil {
+		_ = s.watcher.Close()
+
+		return err
+	}
+
+	s.wg.Add(1)
+
+	go func() {
+		defer s.wg.Done()
+
+		var timer <-chan time.Time
+
+		for {
+			select {
+			case <-s.ctx.Done():
+				_ = s.watcher.Close()
+
+				return
+
+			case ev, ok := <-s.watcher.Events:
+				if !ok {
+					return
+				}
+
+				if ev.Op == fsnotify.Chmod {
+					continue
+				}
+
+				timer = time.After(staticRebuildDebounce)
+
+			case _, ok := <-s.watcher.Errors:
+				if !ok {
+					return
+				}
+
+			case <-timer:
+				timer = nil
+
+				s.send(apiv1.RunOutputResponse_STREAM_STDOUT, "Change detected, rebuilding...")
+
+				if err := s.build(s.ctx); err != nil && s.ctx.Err() == nil {
+					s.send(apiv1.RunOutputResponse_STREAM_STDERR, fmt.Sprintf("Rebuild failed: %s", err))
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (s *staticServer) Stop(timeout time.Duration) error {
+	if s.cancel == nil {
+		return nil
+	}
+
+	s.cancel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	err := s.srv.Shutdown(ctx)
+
+	s.wg.Wait()
+
+	return err
+}
+
+func (s *staticServer) Wait() error {
+	if s.cancel == nil {
+		return nil
+	}
+
+	<-s.done
+
+	return s.err
+}
@@ -0,0 +1,83 @@
+package run
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/outblocks/outblocks-cli/pkg/config"
+)
+
+func writeStaticFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func TestStaticHandler(t *testing.T) {
+	t.Parallel()
+
+	dir := writeStaticFiles(t, map[string]string{
+		"index.html":       "index",
+		"404.html":         "not found",
+		"about/index.html": "about",
+		"app.js":           "js",
+	})
+
+	tests := []struct {
+		name                string
+		routing             string
+		removeTrailingSlash bool
+		path                string
+		status              int
+		body                string
+	}{
+		{"react file", config.StaticAppRoutingReact, false, "/app.js", http.StatusOK, "js"},
+		{"react fallback", config.StaticAppRoutingReact, false, "/some/route", http.StatusOK, "index"},
+		{"gatsby dir index", config.StaticAppRoutingGatsby, false, "/about/", http.StatusOK, "about"},
+		{"gatsby not found", config.StaticAppRoutingGatsby, false, "/missing", http.StatusNotFound, "not found"},
+		{"gatsby trailing slash", config.StaticAppRoutingGatsby, true, "/about/", http.StatusMovedPermanently, ""},
+		{"disabled not found", config.StaticAppRoutingDisabled, false, "/missing", http.StatusNotFound, "not found"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			NewStaticHandler(dir, tt.routing, tt.removeTrailingSlash).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, http.NoBody))
+
+			if rec.Code != tt.status {
+				t.Fatalf("unexpected status: got %d want %d", rec.Code, tt.status)
+			}
+
+			if tt.body == "" {
+				return
+			}
+
+			body, _ := io.ReadAll(rec.Body)
+			if string(body) != tt.body {
+				t.Fatalf("unexpected body: got %q want %q", body, tt.body)
+			}
+		})
+	}
+}
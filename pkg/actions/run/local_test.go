@@ -0,0 +1,107 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
+	"github.com/outblocks/outblocks-plugin-go/util/command"
+)
+
+func TestLocalRestart(t *testing.T) {
+	t.Parallel()
+
+	app := &LocalApp{
+		AppRun: &apiv1.AppRun{
+			App: &apiv1.App{Id: "app_service_api", Name: "api", Dir: t.TempDir()},
+		},
+		Command: command.NewStringCommandFromString("echo started && sleep 30"),
+	}
+
+	ret, err := Local(context.Background(), []*LocalApp{app}, nil, NewReadiness())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		for range ret.OutputCh {
+		}
+	}()
+
+	old := ret.App(app.App.Id)
+
+	info, err := ret.Restart(app.App.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info == old || ret.App(app.App.Id) != info {
+		t.Fatal("expected app run info to be replaced after restart")
+	}
+
+	if old.IsRunning() {
+		t.Fatal("expected old app instance to be stopped")
+	}
+
+	if !info.IsRunning() {
+		t.Fatal("expected restarted app to be running")
+	}
+
+	// Stop reports how app exited, which is not relevant here.
+	_ = ret.Stop()
+
+	if _, err := ret.Restart(app.App.Id); err == nil {
+		t.Fatal("expected restart to fail after stop")
+	}
+
+	// Stop is safe to call more than once.
+	if err := ret.Stop(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLocalRestartPending(t *testing.T) {
+	t.Parallel()
+
+	app := &LocalApp{
+		AppRun: &apiv1.AppRun{
+			App: &apiv1.App{Id: "app_service_api", Name: "api", Dir: t.TempDir()},
+		},
+		Command: command.NewStringCommandFromString("echo started && sleep 30"),
+		// Prerequisite never becomes ready.
+		DependsOn: []string{"dep_db"},
+	}
+
+	ret, err := Local(context.Background(), []*LocalApp{app}, nil, NewReadiness())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		for range ret.OutputCh {
+		}
+	}()
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := ret.Restart(app.App.Id)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("restart of app waiting for its prerequisites is blocked")
+	}
+
+	if !ret.App(app.App.Id).IsRunning() {
+		t.Fatal("expected restarted app to be running")
+	}
+
+	_ = ret.Stop()
+}
@@ -0,0 +1,393 @@
+package run
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ansel1/merry/v2"
+	"github.com/outblocks/outblocks-cli/internal/fileutil"
+)
+
+const (
+	LogStoreMaxSize    = 10 * 1024 * 1024
+	LogStoreMaxBackups = 3
+
+	logStoreExt          = ".log"
+	logStorePollInterval = 500 * time.Millisecond
+)
+
+const (
+	LogStreamStdout = "stdout"
+	LogStreamStderr = "stderr"
+)
+
+// LogRecord is a single line of local run output as persisted in log store.
+type LogRecord struct {
+	Source  string    `json:"source"`
+	Time    time.Time `json:"time"`
+	Stream  string    `json:"stream,omitempty"`
+	Message string    `json:"message"`
+}
+
+// LogStore persists local run output per app and dependency as JSON lines, rotating files once they grow too big.
+type LogStore struct {
+	dir        string
+	maxSize    int64
+	maxBackups int
+
+	mu    sync.Mutex
+	files map[string]*logStoreFile
+}
+
+type logStoreFile struct {
+	f    *os.File
+	size int64
+}
+
+func NewLogStore(dir string) (*LogStore, error) {
+	if err := fileutil.MkdirAll(dir, 0o755); err != nil {
+		return nil, merry.Errorf("cannot create local logs dir: %w", err)
+	}
+
+	return &LogStore{
+		dir:        dir,
+		maxSize:    LogStoreMaxSize,
+		maxBackups: LogStoreMaxBackups,
+		files:      make(map[string]*logStoreFile),
+	}, nil
+}
+
+func logStorePath(dir, source string) string {
+	return filepath.Join(dir, source+logStoreExt)
+}
+
+func (s *LogStore) Write(rec *LogRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	data = append(data, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lf, ok := s.files[rec.Source]
+	if ok && lf.size+int64(len(data)) > s.maxSize {
+		if err := s.rotate(rec.Source, lf); err != nil {
+			return err
+		}
+
+		ok = false
+	}
+
+	if !ok {
+		lf, err = s.open(rec.Source)
+		if err != nil {
+			return err
+		}
+	}
+
+	n, err := lf.f.Write(data)
+	lf.size += int64(n)
+
+	return err
+}
+
+func (s *LogStore) open(source string) (*logStoreFile, error) {
+	f, err := os.OpenFile(logStorePath(s.dir, source), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, merry.Errorf("cannot open local log file: %w", err)
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+
+		return nil, err
+	}
+
+	lf := &logStoreFile{f: f, size: stat.Size()}
+	s.files[source] = lf
+
+	return lf, nil
+}
+
+// rotate shifts <source>.log to <source>.log.1 and so on, dropping the oldest backup.
+func (s *LogStore) rotate(source string, lf *logStoreFile) error {
+	_ = lf.f.Close()
+
+	delete(s.files, source)
+
+	path := logStorePath(s.dir, source)
+
+	_ = os.Remove(fmt.Sprintf("%s.%d", path, s.maxBackups))
+
+	for i := s.maxBackups - 1; i > 0; i-- {
+		err := os.Rename(fmt.Sprintf("%s.%d", path, i), fmt.Sprintf("%s.%d", path, i+1))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	if s.maxBackups == 0 {
+		return os.Remove(path)
+	}
+
+	return os.Rename(path, path+".1")
+}
+
+func (s *LogStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var firstErr error
+
+	for source, lf := range s.files {
+		if err := lf.f.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+
+		delete(s.files, source)
+	}
+
+	return firstErr
+}
+
+// LogStoreSources returns ids of all apps and dependencies that have output stored in given dir.
+func LogStoreSources(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var sources []string
+
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), logStoreExt) {
+			continue
+		}
+
+		sources = append(sources, strings.TrimSuffix(e.Name(), logStoreExt))
+	}
+
+	sort.Strings(sources)
+
+	return sources, nil
+}
+
+// LogStoreOffset is a position in current file of source up to which it was read.
+type LogStoreOffset struct {
+	Offset int64
+	file   os.FileInfo
+}
+
+// ReadLogStore reads all stored records of given sources, including rotated ones, ordered by time.
+// It also returns offsets up to which current files of sources were read, to continue from with FollowLogStore.
+func ReadLogStore(dir string, sources []string) (recs []*LogRecord, offsets map[string]*LogStoreOffset, err error) {
+	offsets = make(map[string]*LogStoreOffset, len(sources))
+
+	for _, source := range sources {
+		path := logStorePath(dir, source)
+		offsets[source] = &LogStoreOffset{}
+
+		for i := LogStoreMaxBackups; i >= 0; i-- {
+			p := path
+			if i > 0 {
+				p = fmt.Sprintf("%s.%d", path, i)
+			}
+
+			f, err := os.Open(p)
+			if err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					continue
+				}
+
+				return nil, nil, err
+			}
+
+			stat, err := f.Stat()
+			if err != nil {
+				_ = f.Close()
+
+				return nil, nil, err
+			}
+
+			n, err := readLogRecords(f, func(rec *LogRecord) {
+				recs = append(recs, rec)
+			})
+
+			_ = f.Close()
+
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if i == 0 {
+				offsets[source] = &LogStoreOffset{Offset: n, file: stat}
+			}
+		}
+	}
+
+	sort.SliceStable(recs, func(i, j int) bool {
+		return recs[i].Time.Before(recs[j].Time)
+	})
+
+	return recs, offsets, nil
+}
+
+// readLogRecords reads complete lines from r, returning number of bytes consumed.
+// Incomplete trailing line is left for the next read.
+func readLogRecords(r io.Reader, cb func(*LogRecord)) (int64, error) {
+	br := bufio.NewReader(r)
+
+	var read int64
+
+	for {
+		line, err := br.ReadBytes('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return read, nil
+			}
+
+			return read, err
+		}
+
+		read += int64(len(line))
+
+		var rec LogRecord
+
+		if json.Unmarshal(line, &rec) != nil {
+			continue
+		}
+
+		cb(&rec)
+	}
+}
+
+// FollowLogStore calls cb for every record appended to files of sources after given offsets, as returned by ReadLogStore,
+// until context is done. Sources that appear later are followed from the start if match returns true for them.
+// Files that get rotated are followed from the start of the new file, after the rest of the rotated one is read.
+func FollowLogStore(ctx context.Context, dir string, offsets map[string]*LogStoreOffset, match func(source string) bool, cb func(*LogRecord)) error {
+	ticker := time.NewTicker(logStorePollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		all, err := LogStoreSources(dir)
+		if err != nil {
+			return err
+		}
+
+		for _, source := range all {
+			if _, ok := offsets[source]; !ok && match != nil && match(source) {
+				offsets[source] = &LogStoreOffset{}
+			}
+		}
+
+		sources := make([]string, 0, len(offsets))
+
+		for source := range offsets {
+			sources = append(sources, source)
+		}
+
+		sort.Strings(sources)
+
+		for _, source := range sources {
+			if err := followLogFile(logStorePath(dir, source), offsets[source], cb); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func followLogFile(path string, offset *LogStoreOffset, cb func(*LogRecord)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	defer f.Close() //nolint:errcheck
+
+	stat, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case offset.file != nil && !os.SameFile(offset.file, stat):
+		// File was rotated, records appended to it before rotation are in the first backup.
+		if err := followRotatedLogFile(path+".1", offset, cb); err != nil {
+			return err
+		}
+
+		offset.Offset = 0
+	case stat.Size() < offset.Offset:
+		// File was truncated in place.
+		offset.Offset = 0
+	}
+
+	offset.file = stat
+
+	if stat.Size() == offset.Offset {
+		return nil
+	}
+
+	if _, err := f.Seek(offset.Offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	n, err := readLogRecords(f, cb)
+	offset.Offset += n
+
+	return err
+}
+
+func followRotatedLogFile(path string, offset *LogStoreOffset, cb func(*LogRecord)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	defer f.Close() //nolint:errcheck
+
+	stat, err := f.Stat()
+	if err != nil || !os.SameFile(offset.file, stat) || stat.Size() <= offset.Offset {
+		return err
+	}
+
+	if _, err := f.Seek(offset.Offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	_, err = readLogRecords(f, cb)
+
+	return err
+}
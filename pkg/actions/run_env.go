@@ -0,0 +1,356 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ansel1/merry/v2"
+	"github.com/outblocks/outblocks-cli/internal/fileutil"
+	"github.com/outblocks/outblocks-cli/internal/util"
+	"github.com/outblocks/outblocks-cli/pkg/actions/run"
+	"github.com/outblocks/outblocks-cli/pkg/config"
+	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
+	"github.com/outblocks/outblocks-plugin-go/types"
+	plugin_util "github.com/outblocks/outblocks-plugin-go/util"
+)
+
+const (
+	RunEnvFormatDotenv = "dotenv"
+	RunEnvFormatJSON   = "json"
+	RunEnvFormatShell  = "shell"
+
+	runPortsFile   = "run-ports.json"
+	runDepVarsFile = "run-deps.json"
+)
+
+var RunEnvFormats = []string{RunEnvFormatDotenv, RunEnvFormatJSON, RunEnvFormatShell}
+
+type RunEnvOptions struct {
+	App      string
+	Format   string
+	PinPorts bool
+}
+
+func pinnedPortsPath(cfg *config.Project) string {
+	return filepath.Join(cfg.Dir, ".outblocks", runPortsFile)
+}
+
+func loadPinnedPorts(cfg *config.Project) (map[string]int, error) {
+	data, err := os.ReadFile(pinnedPortsPath(cfg))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, merry.Errorf("cannot read pinned ports: %w", err)
+	}
+
+	var ports map[string]int
+
+	if err := json.Unmarshal(data, &ports); err != nil {
+		return nil, merry.Errorf("cannot parse pinned ports file '%s': %w", pinnedPortsPath(cfg), err)
+	}
+
+	return ports, nil
+}
+
+func (d *Run) savePinnedPorts(info *runInfo) error {
+	ports := make(map[string]int, len(d.pinnedPorts)+len(info.apps)+len(info.deps))
+
+	for id, port := range d.pinnedPorts {
+		ports[id] = port
+	}
+
+	for _, app := range info.apps {
+		ports[app.App.Id] = int(app.Port)
+	}
+
+	for _, dep := range info.deps {
+		ports[config.ComputeDependencyID(dep.Dependency.Name)] = int(dep.Port)
+	}
+
+	data, err := json.MarshalIndent(ports, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	p := pinnedPortsPath(d.cfg)
+
+	if err := fileutil.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return merry.Errorf("cannot create pinned ports dir: %w", err)
+	}
+
+	if err := fileutil.WriteFile(p, append(data, '\n'), 0o644); err != nil {
+		return merry.Errorf("cannot save pinned ports: %w", err)
+	}
+
+	d.log.Debugf("Pinned ports saved to '%s'.\n", p)
+
+	return nil
+}
+
+// runDepVars are vars of dependency returned by plugin when it was last run on port.
+type runDepVars struct {
+	Port int                    `json:"port"`
+	Vars map[string]interface{} `json:"vars"`
+}
+
+func runDepVarsPath(cfg *config.Project) string {
+	return filepath.Join(cfg.Dir, ".outblocks", runDepVarsFile)
+}
+
+func readRunDepVars(cfg *config.Project) (map[string]*runDepVars, error) {
+	data, err := os.ReadFile(runDepVarsPath(cfg))
+	if os.IsNotExist(err) {
+		return make(map[string]*runDepVars), nil
+	}
+
+	if err != nil {
+		return nil, merry.Errorf("cannot read dependency vars: %w", err)
+	}
+
+	deps := make(map[string]*runDepVars)
+
+	if err := json.Unmarshal(data, &deps); err != nil {
+		return nil, merry.Errorf("cannot parse dependency vars file '%s': %w", runDepVarsPath(cfg), err)
+	}
+
+	return deps, nil
+}
+
+// saveRunDepVars records vars of dependencies as returned by plugins along with ports they run on,
+// so that env of apps can be exported later without starting dependencies.
+func (d *Run) saveRunDepVars(info *runInfo, depVars map[string]interface{}) error {
+	deps, err := readRunDepVars(d.cfg)
+	if err != nil {
+		return err
+	}
+
+	for _, dep := range info.deps {
+		vars, ok := depVars[dep.Dependency.Name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		deps[dep.Dependency.Name] = &runDepVars{
+			Port: int(dep.Port),
+			Vars: vars,
+		}
+	}
+
+	data, err := json.MarshalIndent(deps, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	p := runDepVarsPath(d.cfg)
+
+	if err := fileutil.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return merry.Errorf("cannot create dependency vars dir: %w", err)
+	}
+
+	// Dependency vars may contain credentials of local dependencies.
+	if err := fileutil.WriteFile(p, append(data, '\n'), 0o600); err != nil {
+		return merry.Errorf("cannot save dependency vars: %w", err)
+	}
+
+	return nil
+}
+
+// loadRunDepVars returns recorded vars of dependencies keyed by dependency name.
+// Vars recorded for a different port than dependency would run on now are stale and skipped.
+func (d *Run) loadRunDepVars(info *runInfo) (map[string]interface{}, error) {
+	deps, err := readRunDepVars(d.cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	depVars := make(map[string]interface{})
+
+	for _, dep := range info.deps {
+		rec, ok := deps[dep.Dependency.Name]
+		if !ok || rec.Port != int(dep.Port) {
+			continue
+		}
+
+		depVars[dep.Dependency.Name] = rec.Vars
+	}
+
+	return depVars, nil
+}
+
+func (d *Run) findRunApp(info *runInfo, target string) (*apiv1.AppRun, error) {
+	matcher := util.NewTargetMatcher()
+
+	if err := matcher.AddApp(target); err != nil {
+		return nil, err
+	}
+
+	var found []*apiv1.AppRun
+
+	for _, app := range info.apps {
+		if matcher.Matches(app.App.Id) {
+			found = append(found, app)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, merry.Errorf("app '%s' not found or not selected to run", target)
+	case 1:
+		return found[0], nil
+	}
+
+	return nil, merry.Errorf("app name '%s' is ambiguous, use <type>.<name> form, e.g.: %s.%s", target, found[0].App.Type, found[0].App.Name)
+}
+
+// Env prints environment that given app would receive when run directly, without starting anything.
+// Dependency vars are taken from last run of dependencies on the same ports, otherwise they are left unexpanded.
+func (d *Run) Env(ctx context.Context, w io.Writer, opts *RunEnvOptions) error {
+	if !plugin_util.StringSliceContains(RunEnvFormats, opts.Format) {
+		return merry.Errorf("unknown env format '%s', supported formats: %s", opts.Format, strings.Join(RunEnvFormats, ", "))
+	}
+
+	info, err := d.prepareRun(d.cfg)
+	if err != nil {
+		return err
+	}
+
+	if d.opts.RemoteState != nil {
+		info.remoteApps, err = d.loadRemoteApps(ctx, info)
+		if err != nil {
+			return err
+		}
+	}
+
+	appRun, err := d.findRunApp(info, opts.App)
+	if err != nil {
+		return err
+	}
+
+	if opts.PinPorts {
+		if err := d.savePinnedPorts(info); err != nil {
+			return err
+		}
+	}
+
+	localApp := &run.LocalApp{AppRun: appRun}
+
+	if static, ok := d.cfg.AppByID(appRun.App.Id).(*config.StaticApp); ok && static.RunInfo().Command.IsEmpty() {
+		localApp.Static = d.localStaticApp(static)
+	}
+
+	depVars, err := d.loadRunDepVars(info)
+	if err != nil {
+		return err
+	}
+
+	localDepVars(depVars)
+
+	unresolved := make(map[string]struct{})
+
+	if err := expandLocalAppEnv(localApp, newDeferredDepsEvaluator(types.VarsForApp(runAppVars(info), appRun.App, depVars), unresolved)); err != nil {
+		return err
+	}
+
+	d.warnUnresolvedDepVars(unresolved)
+
+	return writeRunEnv(w, localApp.App.Env, opts.Format)
+}
+
+// newDeferredDepsEvaluator returns evaluator that leaves unknown dependency vars unexpanded as dependencies are not running,
+// collecting them in unresolved.
+func newDeferredDepsEvaluator(vars map[string]interface{}, unresolved map[string]struct{}) *util.VarEvaluator {
+	eval := util.NewVarEvaluator(vars)
+	eval.WithKeyGetter(func(c *plugin_util.VarContext, vars map[string]interface{}) (interface{}, error) {
+		val, err := plugin_util.DefaultVarKeyGetter(c, vars)
+		if err != nil && strings.HasPrefix(c.Token, "dep.") {
+			unresolved[c.Token] = struct{}{}
+
+			return fmt.Sprintf("%%{%s}", c.Token), nil
+		}
+
+		return val, err
+	})
+
+	return eval
+}
+
+func (d *Run) warnUnresolvedDepVars(unresolved map[string]struct{}) {
+	for _, k := range sortedKeys(unresolved) {
+		d.log.Warnf("Dependency var '%%{%s}' is only known once dependency runs on its current port, left unexpanded. Use 'ok run' with pinned ports to record it.\n", k)
+	}
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func writeRunEnv(w io.Writer, env map[string]string, format string) error {
+	if format == RunEnvFormatJSON {
+		data, err := json.MarshalIndent(env, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(w, string(data))
+
+		return err
+	}
+
+	keys := make([]string, 0, len(env))
+
+	for k := range env {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		var err error
+
+		switch format {
+		case RunEnvFormatShell:
+			_, err = fmt.Fprintf(w, "export %s=%s\n", k, shellQuote(env[k]))
+		default:
+			_, err = fmt.Fprintf(w, "%s=%s\n", k, dotenvQuote(env[k]))
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func dotenvQuote(v string) string {
+	if v != "" && strings.IndexFunc(v, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("_-./:@", r))
+	}) == -1 {
+		return v
+	}
+
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "$", `\$`)
+
+	return `"` + r.Replace(v) + `"`
+}
+
+// shellQuote quotes value for POSIX shell.
+func shellQuote(v string) string {
+	return "'" + strings.ReplaceAll(v, "'", `'\''`) + "'"
+}
@@ -0,0 +1,159 @@
+package actions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/outblocks/outblocks-cli/internal/util"
+	"github.com/outblocks/outblocks-cli/pkg/config"
+	"github.com/outblocks/outblocks-cli/pkg/lockfile"
+	"github.com/outblocks/outblocks-cli/pkg/logger"
+	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
+)
+
+func loadRunEnvProject(t *testing.T, dir string) *config.Project {
+	t.Helper()
+
+	p, err := config.LoadProjectConfigData(filepath.Join(dir, "project.outblocks.yaml"), []byte(`name: test
+dependencies:
+  db:
+    type: postgresql
+`), nil, nil, &config.ProjectOptions{Env: "dev"}, &lockfile.Lockfile{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	appDir := filepath.Join(dir, "api")
+
+	if err := os.MkdirAll(appDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(appDir, "api.outblocks.yaml")
+
+	err = os.WriteFile(file, []byte(`name: api
+type: service
+env:
+  DB_URL: "%{dep.db.url}"
+  GREETING: hello world
+run:
+  command: go run .
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.LoadAppFile(file, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.Normalize(); err != nil {
+		t.Fatal(err)
+	}
+
+	return p
+}
+
+func depRun(name string, port int) *apiv1.DependencyRun {
+	return &apiv1.DependencyRun{Dependency: &apiv1.Dependency{Name: name}, Port: int32(port)}
+}
+
+func TestRunEnv(t *testing.T) { //nolint:gocyclo
+	t.Parallel()
+
+	dir := t.TempDir()
+	cfg := loadRunEnvProject(t, dir)
+
+	env := func(t *testing.T, opts *RunEnvOptions) string {
+		t.Helper()
+
+		var buf bytes.Buffer
+
+		d := NewRun(logger.NewLogger(), cfg, &RunOptions{
+			Direct:     true,
+			ListenIP:   "127.0.0.1",
+			ListenPort: 8000,
+			Targets:    util.NewTargetMatcher(),
+			Skips:      util.NewTargetMatcher(),
+		})
+
+		if err := d.Env(context.Background(), &buf, opts); err != nil {
+			t.Fatal(err)
+		}
+
+		return buf.String()
+	}
+
+	out := env(t, &RunEnvOptions{App: "api", Format: RunEnvFormatDotenv})
+	if !strings.Contains(out, "PORT=8001\n") || !strings.Contains(out, `GREETING="hello world"`) || !strings.Contains(out, `DB_URL="%{dep.db.url}"`) {
+		t.Fatalf("unexpected dotenv output:\n%s", out)
+	}
+
+	out = env(t, &RunEnvOptions{App: "service.api", Format: RunEnvFormatShell})
+	if !strings.Contains(out, "export PORT='8001'\n") || !strings.Contains(out, "export GREETING='hello world'\n") {
+		t.Fatalf("unexpected shell output:\n%s", out)
+	}
+
+	// Pinned ports are used by next exports.
+	env(t, &RunEnvOptions{App: "api", Format: RunEnvFormatDotenv, PinPorts: true})
+
+	data, err := os.ReadFile(filepath.Join(dir, ".outblocks", runPortsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ports map[string]int
+
+	if err := json.Unmarshal(data, &ports); err != nil {
+		t.Fatal(err)
+	}
+
+	if ports["app_service_api"] != 8001 || ports["dep_db"] != 8002 {
+		t.Fatalf("unexpected pinned ports: %v", ports)
+	}
+
+	ports["app_service_api"] = 9001
+
+	data, _ = json.Marshal(ports)
+
+	if err := os.WriteFile(filepath.Join(dir, ".outblocks", runPortsFile), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Dependency vars recorded by run on the same port are resolved with their local counterparts.
+	d := NewRun(logger.NewLogger(), cfg, &RunOptions{})
+
+	err = d.saveRunDepVars(&runInfo{deps: []*apiv1.DependencyRun{depRun("db", 8002)}}, map[string]interface{}{
+		"db": map[string]interface{}{"url": "postgres://db:5432", "local:url": "postgres://localhost:8002"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var res map[string]string
+
+	if err := json.Unmarshal([]byte(env(t, &RunEnvOptions{App: "api", Format: RunEnvFormatJSON})), &res); err != nil {
+		t.Fatal(err)
+	}
+
+	if res["PORT"] != "9001" || res["DB_URL"] != "postgres://localhost:8002" || res["GREETING"] != "hello world" {
+		t.Fatalf("unexpected json output: %v", res)
+	}
+
+	// Vars recorded for different port are stale.
+	err = d.saveRunDepVars(&runInfo{deps: []*apiv1.DependencyRun{depRun("db", 8005)}}, map[string]interface{}{
+		"db": map[string]interface{}{"url": "postgres://db:5432"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if out := env(t, &RunEnvOptions{App: "api", Format: RunEnvFormatDotenv}); !strings.Contains(out, `DB_URL="%{dep.db.url}"`) {
+		t.Fatalf("expected stale dependency var to be left unexpanded:\n%s", out)
+	}
+}
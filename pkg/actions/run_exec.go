@@ -0,0 +1,186 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/ansel1/merry/v2"
+	"github.com/outblocks/outblocks-cli/internal/util"
+	"github.com/outblocks/outblocks-cli/pkg/actions/run"
+	"github.com/outblocks/outblocks-cli/pkg/plugins"
+	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
+	"github.com/outblocks/outblocks-plugin-go/types"
+	plugin_util "github.com/outblocks/outblocks-plugin-go/util"
+	"github.com/outblocks/outblocks-plugin-go/util/errgroup"
+)
+
+type RunExecOptions struct {
+	App      string
+	Command  []string
+	WithDeps bool
+}
+
+// Exec runs one-off command in app dir with the same environment app would receive when run directly.
+// Exit code of command is propagated through ErrExit.
+func (d *Run) Exec(ctx context.Context, opts *RunExecOptions) error {
+	if len(opts.Command) == 0 {
+		return merry.New("command to execute is required")
+	}
+
+	info, err := d.prepareRun(d.cfg)
+	if err != nil {
+		return err
+	}
+
+	if d.opts.RemoteState != nil {
+		info.remoteApps, err = d.loadRemoteApps(ctx, info)
+		if err != nil {
+			return err
+		}
+	}
+
+	appRun, err := d.findRunApp(info, opts.App)
+	if err != nil {
+		return err
+	}
+
+	localApp := &run.LocalApp{AppRun: appRun}
+
+	var eval *util.VarEvaluator
+
+	if opts.WithDeps {
+		// Dependencies are stopped explicitly once command exits, interrupting command should not stop them.
+		depsCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		defer cancel()
+
+		depVars, stop, err := d.startAppDependencies(depsCtx, info, appRun)
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			if err := stop(); err != nil {
+				d.log.Warnf("Error stopping dependencies: %s\n", err)
+			}
+		}()
+
+		eval = util.NewVarEvaluator(types.VarsForApp(runAppVars(info), appRun.App, depVars))
+	} else {
+		unresolved := make(map[string]struct{})
+		eval = newDeferredDepsEvaluator(types.VarsForApp(runAppVars(info), appRun.App, nil), unresolved)
+
+		defer d.warnUnresolvedDepVars(unresolved)
+	}
+
+	if err := expandLocalAppEnv(localApp, eval); err != nil {
+		return err
+	}
+
+	// App dir is relative to project dir, command may be executed from any of its subdirs.
+	return execCommand(filepath.Join(d.cfg.Dir, appRun.App.Dir), localApp.App.Env, opts.Command)
+}
+
+// startAppDependencies starts dependencies needed by app through their plugins and waits for them to accept connections.
+func (d *Run) startAppDependencies(ctx context.Context, info *runInfo, app *apiv1.AppRun) (depVars map[string]interface{}, stop func() error, err error) {
+	runMap := make(map[*plugins.Plugin]*apiv1.RunRequest)
+
+	var deps []*apiv1.DependencyRun
+
+	for _, dep := range info.deps {
+		if _, ok := app.App.Needs[dep.Dependency.Name]; !ok {
+			continue
+		}
+
+		runPlugin := d.cfg.DependencyByName(dep.Dependency.Name).RunPlugin()
+
+		if _, ok := runMap[runPlugin]; !ok {
+			runMap[runPlugin] = &apiv1.RunRequest{
+				Args: plugin_util.MustNewStruct(runPlugin.CommandArgs(runCommand)),
+			}
+		}
+
+		runMap[runPlugin].Dependencies = append(runMap[runPlugin].Dependencies, dep)
+		deps = append(deps, dep)
+	}
+
+	if len(deps) == 0 {
+		return make(map[string]interface{}), func() error { return nil }, nil
+	}
+
+	spinner, _ := d.log.Spinner().Start("Starting dependencies...")
+	defer spinner.Stop()
+
+	pluginRet, err := run.ThroughPlugin(ctx, runMap)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	go func() {
+		for msg := range pluginRet.OutputCh {
+			d.log.Debugf("DEP:%s %s\n", msg.Name, plugin_util.StripAnsiControl(msg.Message))
+		}
+	}()
+
+	stop = pluginRet.Stop
+
+	waitCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	g, _ := errgroup.WithContext(waitCtx)
+
+	for _, dep := range deps {
+		dep := dep
+
+		g.Go(func() error {
+			return waitDependency(waitCtx, dep)
+		})
+	}
+
+	// Dependency that fails to start would never accept connections.
+	go func() {
+		if err := pluginRet.Wait(); err != nil {
+			cancel()
+		}
+	}()
+
+	if err := g.Wait(); err != nil {
+		_ = stop()
+
+		return nil, nil, merry.Errorf("dependencies failed to start: %w", err)
+	}
+
+	depVars = d.pluginDepVars([]*run.PluginRunResult{pluginRet})
+	localDepVars(depVars)
+
+	return depVars, stop, nil
+}
+
+func execCommand(dir string, env map[string]string, args []string) error {
+	cmd := exec.Command(args[0], args[1:]...) //nolint:gosec
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), util.FlattenEnvMap(env)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		code := exitErr.ProcessState.ExitCode()
+
+		// Killed by a signal.
+		if code < 0 {
+			code = 1
+		}
+
+		return &ErrExit{
+			StatusCode: code,
+		}
+	}
+
+	return err
+}
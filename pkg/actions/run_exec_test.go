@@ -0,0 +1,303 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/outblocks/outblocks-cli/internal/util"
+	"github.com/outblocks/outblocks-cli/pkg/config"
+	"github.com/outblocks/outblocks-cli/pkg/lockfile"
+	"github.com/outblocks/outblocks-cli/pkg/logger"
+	"github.com/outblocks/outblocks-cli/pkg/plugins"
+	"github.com/outblocks/outblocks-cli/pkg/server"
+	plugin_go "github.com/outblocks/outblocks-plugin-go"
+	"github.com/outblocks/outblocks-plugin-go/env"
+	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
+	"github.com/outblocks/outblocks-plugin-go/log"
+	"github.com/outblocks/outblocks-plugin-go/util/command"
+)
+
+const (
+	helperRunPluginEnv = "OUTBLOCKS_TEST_HELPER_RUN_PLUGIN"
+	helperExecEnv      = "OUTBLOCKS_TEST_HELPER_EXEC"
+)
+
+// helperRunPlugin is a run plugin built with plugin SDK that starts dependencies by listening on their ports.
+type helperRunPlugin struct{}
+
+func (p *helperRunPlugin) Init(context.Context, env.Enver, log.Logger, apiv1.HostServiceClient) error {
+	return nil
+}
+
+func (p *helperRunPlugin) Start(context.Context, *apiv1.StartRequest) (*apiv1.StartResponse, error) {
+	return &apiv1.StartResponse{}, nil
+}
+
+func (p *helperRunPlugin) ProjectInit(context.Context, *apiv1.ProjectInitRequest) (*apiv1.ProjectInitResponse, error) {
+	return &apiv1.ProjectInitResponse{}, nil
+}
+
+func (p *helperRunPlugin) Run(req *apiv1.RunRequest, stream apiv1.RunPluginService_RunServer) error {
+	vars := make(map[string]*apiv1.RunVars)
+
+	for _, dep := range req.Dependencies {
+		lis, err := net.Listen("tcp", fmt.Sprintf("%s:%d", dep.Ip, dep.Port))
+		if err != nil {
+			return err
+		}
+
+		defer lis.Close() //nolint:errcheck
+
+		go func() {
+			for {
+				conn, err := lis.Accept()
+				if err != nil {
+					return
+				}
+
+				_ = conn.Close()
+			}
+		}()
+
+		vars[config.ComputeDependencyID(dep.Dependency.Name)] = &apiv1.RunVars{Vars: map[string]string{
+			"url":       "postgres://db:5432",
+			"local:url": fmt.Sprintf("postgres://%s:%d", dep.Ip, dep.Port),
+		}}
+	}
+
+	err := stream.Send(&apiv1.RunResponse{Response: &apiv1.RunResponse_Start{Start: &apiv1.RunStartResponse{Vars: vars}}})
+	if err != nil {
+		return err
+	}
+
+	<-stream.Context().Done()
+
+	return nil
+}
+
+// TestHelperRunPlugin is not a real test, it runs helper run plugin when started as a plugin by tests.
+func TestHelperRunPlugin(t *testing.T) {
+	if os.Getenv(helperRunPluginEnv) != "1" {
+		t.Skip("helper plugin process")
+	}
+
+	if err := plugin_go.Serve(&helperRunPlugin{}); err != nil {
+		os.Exit(1)
+	}
+
+	os.Exit(0)
+}
+
+// TestHelperExec is not a real test, it is a command run by exec tests.
+// It exits with code passed in args if DB_URL env var matches expected one.
+func TestHelperExec(t *testing.T) {
+	if os.Getenv(helperExecEnv) != "1" {
+		t.Skip("helper exec process")
+	}
+
+	args := flag.Args()
+
+	if os.Getenv("DB_URL") != args[0] {
+		fmt.Fprintf(os.Stderr, "unexpected DB_URL: %s\n", os.Getenv("DB_URL"))
+		os.Exit(100)
+	}
+
+	code, _ := strconv.Atoi(args[1])
+
+	os.Exit(code)
+}
+
+func freePort(t *testing.T) int {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer lis.Close() //nolint:errcheck
+
+	return lis.Addr().(*net.TCPAddr).Port
+}
+
+// loadRunExecProject loads project with app needing a dependency run by helper run plugin.
+func loadRunExecProject(t *testing.T, depPort int) *config.Project {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	p, err := config.LoadProjectConfigData(filepath.Join(dir, "project.outblocks.yaml"), []byte(fmt.Sprintf(`name: test
+dependencies:
+  db:
+    type: postgresql
+    run:
+      port: %d
+`, depPort)), nil, nil, &config.ProjectOptions{Env: "dev"}, &lockfile.Lockfile{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	appDir := filepath.Join(dir, "api")
+
+	if err := os.MkdirAll(appDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(appDir, "api.outblocks.yaml")
+
+	err = os.WriteFile(file, []byte(`name: api
+type: service
+needs:
+  db: {}
+env:
+  DB_URL: "%{dep.db.url}"
+  `+helperExecEnv+`: "1"
+run:
+  command: go run .
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.LoadAppFile(file, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.Normalize(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := server.NewServer(logger.NewLogger(), map[string]interface{}{})
+
+	if err := srv.Serve(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(srv.Stop)
+
+	plug := &plugins.Plugin{
+		Name:           "helper",
+		Cmd:            map[string]*command.StringCommand{"default": command.NewStringCommandFromArray([]string{"env", helperRunPluginEnv + "=1", os.Args[0], "-test.run=^TestHelperRunPlugin$"})},
+		Actions:        []string{"deploy", "run"},
+		SupportedTypes: []*plugins.PluginType{{Type: "postgresql"}},
+	}
+
+	if err := plug.Normalize(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := plug.Prepare(context.Background(), logger.NewLogger(), "dev", p.ID(), p.Name, p.Dir, srv, nil, "$.plugins[0]", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = plug.Stop() })
+
+	p.SetLoadedPlugins([]*plugins.Plugin{plug})
+
+	if err := p.DependencyByName("db").Check("db", p); err != nil {
+		t.Fatal(err)
+	}
+
+	return p
+}
+
+func TestRunExec(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		withDeps bool
+		code     int
+	}{
+		{"without dependencies", false, 0},
+		{"exit code propagated", false, 3},
+		{"with dependencies", true, 0},
+		{"with dependencies exit code propagated", true, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			depPort := freePort(t)
+
+			// Dependency vars are left unexpanded when dependencies are not started.
+			dbURL := "%{dep.db.url}"
+			if tt.withDeps {
+				dbURL = fmt.Sprintf("postgres://127.0.0.1:%d", depPort)
+			}
+
+			d := NewRun(logger.NewLogger(), loadRunExecProject(t, depPort), &RunOptions{
+				ListenIP:   "127.0.0.1",
+				ListenPort: 8000,
+				Targets:    util.NewTargetMatcher(),
+				Skips:      util.NewTargetMatcher(),
+			})
+
+			err := d.Exec(context.Background(), &RunExecOptions{
+				App:      "api",
+				Command:  []string{os.Args[0], "-test.run=^TestHelperExec$", "--", dbURL, strconv.Itoa(tt.code)},
+				WithDeps: tt.withDeps,
+			})
+
+			var exitErr *ErrExit
+
+			switch {
+			case tt.code == 0 && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.code != 0 && (!errors.As(err, &exitErr) || exitErr.StatusCode != tt.code):
+				t.Fatalf("expected exit code %d, got: %v", tt.code, err)
+			}
+
+			// Dependencies are stopped once command exits.
+			if conn, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", depPort)); err == nil {
+				_ = conn.Close()
+
+				t.Error("expected dependency to be stopped")
+			}
+		})
+	}
+}
+
+func TestRunExecDependencyFailure(t *testing.T) {
+	t.Parallel()
+
+	// Dependency cannot start as its port is already taken.
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer lis.Close() //nolint:errcheck
+
+	cfg := loadRunExecProject(t, lis.Addr().(*net.TCPAddr).Port)
+
+	d := NewRun(logger.NewLogger(), cfg, &RunOptions{
+		ListenIP:   "127.0.0.1",
+		ListenPort: 8000,
+		Targets:    util.NewTargetMatcher(),
+		Skips:      util.NewTargetMatcher(),
+	})
+
+	err = d.Exec(context.Background(), &RunExecOptions{
+		App:      "api",
+		Command:  []string{os.Args[0], "-test.run=^TestHelperExec$", "--", "", "0"},
+		WithDeps: true,
+	})
+	if err == nil {
+		t.Fatal("expected error when dependency fails to start")
+	}
+
+	var exitErr *ErrExit
+
+	if errors.As(err, &exitErr) {
+		t.Fatalf("command should not be run when dependency fails to start, got: %v", err)
+	}
+}
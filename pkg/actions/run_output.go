@@ -0,0 +1,266 @@
+package actions
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ansel1/merry/v2"
+	"github.com/outblocks/outblocks-cli/internal/fileutil"
+	"github.com/outblocks/outblocks-cli/internal/util"
+	"github.com/outblocks/outblocks-cli/pkg/actions/run"
+	"github.com/outblocks/outblocks-cli/pkg/config"
+	"github.com/outblocks/outblocks-cli/pkg/logger"
+	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
+	plugin_util "github.com/outblocks/outblocks-plugin-go/util"
+	"github.com/pterm/pterm"
+)
+
+const (
+	runOutputTimeFormat = "15:04:05.000"
+	localLogsDir        = "logs/local"
+)
+
+var runOutputColors = []pterm.Color{
+	pterm.FgGreen,
+	pterm.FgCyan,
+	pterm.FgMagenta,
+	pterm.FgYellow,
+	pterm.FgBlue,
+	pterm.FgLightGreen,
+	pterm.FgLightCyan,
+	pterm.FgLightMagenta,
+	pterm.FgLightYellow,
+	pterm.FgLightBlue,
+}
+
+type runOutput struct {
+	log        logger.Logger
+	cfg        *config.Project
+	only       *util.TargetMatcher
+	timestamps bool
+	logDir     string
+	store      *run.LogStore
+
+	// sink receives every line instead of it being printed, used by dashboard.
+	sink func(id string, stream apiv1.RunOutputResponse_Stream, msg string)
+
+	mu       sync.Mutex
+	files    map[string]*os.File
+	closed   bool
+	storeErr bool
+}
+
+// LocalLogsPath returns path of local run log store of project.
+func LocalLogsPath(cfg *config.Project) string {
+	return filepath.Join(cfg.Dir, ".outblocks", localLogsDir)
+}
+
+func newRunOutput(log logger.Logger, cfg *config.Project, opts *RunOptions) (*runOutput, error) {
+	o := &runOutput{
+		log:        log,
+		cfg:        cfg,
+		only:       opts.OnlyLogs,
+		timestamps: opts.LogTimestamps,
+		logDir:     opts.LogDir,
+		files:      make(map[string]*os.File),
+	}
+
+	if o.logDir != "" {
+		if err := fileutil.MkdirAll(o.logDir, 0o755); err != nil {
+			return nil, merry.Errorf("cannot create log dir: %w", err)
+		}
+	}
+
+	var err error
+
+	// Local log store is not essential to run apps.
+	o.store, err = run.NewLogStore(LocalLogsPath(cfg))
+	if err != nil {
+		o.log.Warnf("Cannot persist local run output, it will not be available with 'ok logs --local': %s\n", err)
+	}
+
+	return o, nil
+}
+
+// runOutputColor returns a color for given id that stays the same between runs.
+func runOutputColor(id string) pterm.Color {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(id))
+
+	return runOutputColors[h.Sum32()%uint32(len(runOutputColors))]
+}
+
+func (o *runOutput) source(r *apiv1.RunOutputResponse) (id, prefix string) {
+	switch r.Source {
+	case apiv1.RunOutputResponse_SOURCE_APP:
+		app := o.cfg.AppByID(r.Id)
+		if app == nil {
+			return r.Id, fmt.Sprintf("APP:%s:", r.Name)
+		}
+
+		return app.ID(), fmt.Sprintf("APP:%s:%s:", app.Type(), app.Name())
+
+	case apiv1.RunOutputResponse_SOURCE_DEPENDENCY:
+		return config.ComputeDependencyID(r.Name), fmt.Sprintf("DEP:%s", r.Name)
+
+	case apiv1.RunOutputResponse_SOURCE_UNSPECIFIED:
+	}
+
+	return "", fmt.Sprintf("UNKNOWN:%s", r.Name)
+}
+
+func (o *runOutput) Handle(r *apiv1.RunOutputResponse) {
+	id, prefix := o.source(r)
+	msg := logger.Redact(plugin_util.StripAnsiControl(r.Message))
+	now := time.Now()
+
+	if o.logDir != "" && id != "" {
+		o.writeFile(id, r.Stream, now, msg)
+	}
+
+	if id != "" {
+		o.storeRecord(id, r.Stream, now, msg)
+	}
+
+	if o.sink != nil {
+		o.sink(id, r.Stream, msg)
+
+		return
+	}
+
+	if !o.shown(id) {
+		return
+	}
+
+	var ts string
+
+	if o.timestamps {
+		ts = pterm.FgGray.Sprint(now.Format(runOutputTimeFormat)) + " "
+	}
+
+	// Stderr is marked with red prefix, which is never used as app color.
+	color := runOutputColor(prefix)
+	if r.Stream == apiv1.RunOutputResponse_STREAM_STDERR {
+		color = pterm.FgRed
+	}
+
+	o.log.Printf("%s%s %s\n", ts, color.Sprint(prefix), msg)
+}
+
+func (o *runOutput) shown(id string) bool {
+	if o.only.IsEmpty() {
+		return true
+	}
+
+	if id == "" {
+		return false
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.only.Matches(id)
+}
+
+func (o *runOutput) writeFile(id string, stream apiv1.RunOutputResponse_Stream, now time.Time, msg string) {
+	name := fmt.Sprintf("%s.stdout.log", id)
+	if stream == apiv1.RunOutputResponse_STREAM_STDERR {
+		name = fmt.Sprintf("%s.stderr.log", id)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.closed {
+		return
+	}
+
+	f, ok := o.files[name]
+	if !ok {
+		var err error
+
+		f, err = os.OpenFile(filepath.Join(o.logDir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			o.log.Errorf("Cannot open log file %s: %s\n", name, err)
+
+			// Don't retry on every line.
+			o.files[name] = nil
+
+			return
+		}
+
+		o.files[name] = f
+	}
+
+	if f == nil {
+		return
+	}
+
+	if o.timestamps {
+		_, _ = fmt.Fprintf(f, "%s %s\n", now.Format(time.RFC3339Nano), msg)
+	} else {
+		_, _ = fmt.Fprintln(f, msg)
+	}
+}
+
+func (o *runOutput) storeRecord(id string, stream apiv1.RunOutputResponse_Stream, now time.Time, msg string) {
+	if o.store == nil {
+		return
+	}
+
+	rec := &run.LogRecord{
+		Source:  id,
+		Time:    now,
+		Stream:  run.LogStreamStdout,
+		Message: msg,
+	}
+
+	if stream == apiv1.RunOutputResponse_STREAM_STDERR {
+		rec.Stream = run.LogStreamStderr
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.closed {
+		return
+	}
+
+	err := o.store.Write(rec)
+
+	// Report only once.
+	if err != nil && !o.storeErr {
+		o.storeErr = true
+
+		o.log.Warnf("Cannot persist local run output: %s\n", err)
+	}
+}
+
+func (o *runOutput) Close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var firstErr error
+
+	if o.store != nil {
+		firstErr = o.store.Close()
+	}
+
+	for _, f := range o.files {
+		if f == nil {
+			continue
+		}
+
+		if err := f.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	o.closed = true
+
+	return firstErr
+}
@@ -0,0 +1,194 @@
+package actions
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/outblocks/outblocks-cli/internal/util"
+	"github.com/outblocks/outblocks-cli/pkg/actions/run"
+	"github.com/outblocks/outblocks-cli/pkg/config"
+	"github.com/outblocks/outblocks-cli/pkg/logger"
+	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
+	"github.com/pterm/pterm"
+)
+
+// printLogger records printed lines without colors.
+type printLogger struct {
+	logger.Logger
+
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *printLogger) Printf(format string, a ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.lines = append(l.lines, pterm.RemoveColorFromString(fmt.Sprintf(format, a...)))
+}
+
+var (
+	runOutputAPI = &apiv1.RunOutputResponse{Source: apiv1.RunOutputResponse_SOURCE_APP, Id: "app_service_api", Name: "api", Message: "listening"}
+	runOutputDB  = &apiv1.RunOutputResponse{Source: apiv1.RunOutputResponse_SOURCE_DEPENDENCY, Name: "db", Message: "ready", Stream: apiv1.RunOutputResponse_STREAM_STDERR}
+	runOutputUnk = &apiv1.RunOutputResponse{Name: "plugin", Message: "unknown source"}
+)
+
+func TestRunOutputFilter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		only []string
+		want []string
+	}{
+		{"all shown without filter", nil, []string{"APP:api: listening", "DEP:db ready", "UNKNOWN:plugin unknown source"}},
+		{"only app", []string{"service.api"}, []string{"APP:api: listening"}},
+		{"only dependency", []string{"dep.db"}, []string{"DEP:db ready"}},
+		{"app and dependency", []string{"api", "db"}, []string{"APP:api: listening", "DEP:db ready"}},
+		{"no match", []string{"service.other"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			only := util.NewTargetMatcher()
+
+			for _, o := range tt.only {
+				if err := only.Add(o); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			log := &printLogger{Logger: logger.NewLogger()}
+
+			o, err := newRunOutput(log, &config.Project{Dir: t.TempDir()}, &RunOptions{OnlyLogs: only})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer o.Close() //nolint:errcheck
+
+			for _, r := range []*apiv1.RunOutputResponse{runOutputAPI, runOutputDB, runOutputUnk} {
+				o.Handle(r)
+			}
+
+			got := strings.Join(log.lines, "")
+			want := ""
+
+			for _, w := range tt.want {
+				want += w + "\n"
+			}
+
+			if got != want {
+				t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestRunOutputLogFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	logDir := filepath.Join(dir, "logs")
+
+	logger.AddRedactedValues("run-output-secret")
+
+	only := util.NewTargetMatcher()
+	_ = only.Add("dep.db")
+
+	o, err := newRunOutput(&printLogger{Logger: logger.NewLogger()}, &config.Project{Dir: dir}, &RunOptions{OnlyLogs: only, LogDir: logDir, LogTimestamps: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Output is written to files and local log store even if it is filtered out from console.
+	o.Handle(runOutputAPI)
+	o.Handle(&apiv1.RunOutputResponse{Source: apiv1.RunOutputResponse_SOURCE_APP, Id: "app_service_api", Message: "\x1b[31mtoken run-output-secret\x1b[0m"})
+	o.Handle(runOutputDB)
+	o.Handle(runOutputUnk)
+
+	if err := o.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Output after close is dropped.
+	o.Handle(runOutputDB)
+
+	tsLine := regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\S+ (.*)$`)
+
+	files := map[string][]string{
+		"app_service_api.stdout.log": {"listening", "token ***"},
+		"dep_db.stderr.log":          {"ready"},
+	}
+
+	entries, err := os.ReadDir(logDir)
+	if err != nil || len(entries) != len(files) {
+		t.Fatalf("unexpected log files: %v, %v", entries, err)
+	}
+
+	for name, want := range files {
+		data, err := os.ReadFile(filepath.Join(logDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+		if len(lines) != len(want) {
+			t.Fatalf("unexpected lines in %s: %q", name, lines)
+		}
+
+		for i, l := range lines {
+			m := tsLine.FindStringSubmatch(l)
+			if m == nil || m[1] != want[i] {
+				t.Errorf("unexpected line %d in %s: %q, want %q with timestamp", i, name, l, want[i])
+			}
+		}
+	}
+
+	recs, _, err := run.ReadLogStore(LocalLogsPath(&config.Project{Dir: dir}), []string{"app_service_api", "dep_db"})
+	if err != nil || len(recs) != 3 {
+		t.Fatalf("unexpected stored records: %v, %v", recs, err)
+	}
+
+	for _, rec := range recs {
+		if rec.Source == "dep_db" && rec.Stream != run.LogStreamStderr {
+			t.Errorf("unexpected stored record stream: %+v", rec)
+		}
+	}
+}
+
+func TestRunOutputWithoutLogStore(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	// Log store dir cannot be created as .outblocks is a file.
+	if err := os.WriteFile(filepath.Join(dir, ".outblocks"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	logDir := filepath.Join(dir, "logs")
+
+	o, err := newRunOutput(logger.NewLogger(), &config.Project{Dir: dir}, &RunOptions{OnlyLogs: util.NewTargetMatcher(), LogDir: logDir})
+	if err != nil {
+		t.Fatalf("run should continue without log store, got: %v", err)
+	}
+
+	o.Handle(&apiv1.RunOutputResponse{Source: apiv1.RunOutputResponse_SOURCE_DEPENDENCY, Name: "db", Message: "ready"})
+
+	if err := o.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(logDir, "dep_db.stdout.log"))
+	if err != nil || string(data) != "ready\n" {
+		t.Fatalf("expected output to be still written to log dir, got: %q, %v", data, err)
+	}
+}
@@ -0,0 +1,184 @@
+package actions
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/outblocks/outblocks-cli/pkg/config"
+	"github.com/outblocks/outblocks-cli/pkg/lockfile"
+	"github.com/outblocks/outblocks-cli/pkg/logger"
+	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
+)
+
+// loadRunRoutingProject loads project with given apps only, as when running with targets.
+func loadRunRoutingProject(t *testing.T, names ...string) *config.Project {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	p, err := config.LoadProjectConfigData(filepath.Join(dir, "project.outblocks.yaml"), []byte("name: test\ndefaults:\n  run:\n    env: {}\n"), nil, nil, &config.ProjectOptions{Env: "dev"}, &lockfile.Lockfile{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apps := map[string]string{
+		"api":      "api.test.local/",
+		"payments": "test.local/payments/",
+	}
+
+	for _, name := range names {
+		u := apps[name]
+		appDir := filepath.Join(dir, name)
+
+		if err := os.MkdirAll(appDir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		file := filepath.Join(appDir, name+".outblocks.yaml")
+
+		if err := os.WriteFile(file, []byte("name: "+name+"\ntype: service\nurl: "+u+"\nrun:\n  command: go run .\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := p.LoadAppFile(file, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := p.Normalize(); err != nil {
+		t.Fatal(err)
+	}
+
+	return p
+}
+
+func TestRunHostsRouting(t *testing.T) {
+	t.Parallel()
+
+	d := NewRun(logger.NewLogger(), loadRunRoutingProject(t, "api", "payments"), &RunOptions{
+		HostsRouting: true,
+		HostsSuffix:  ".test",
+		ListenPort:   8000,
+	})
+
+	info := &runInfo{
+		apps: []*apiv1.AppRun{
+			{App: &apiv1.App{Id: "app_service_api"}, Url: "http://api.test.local.test:8000/", Ip: "127.0.0.1", Port: 8001},
+		},
+		remoteApps: []*apiv1.AppState{
+			// Remote url has different hostname than the one configured for local run.
+			{App: &apiv1.App{Id: "app_service_payments", Url: "https://staging.example.com/payments/"}},
+			// Apps no longer present in local config are routed by their remote url.
+			{App: &apiv1.App{Id: "app_service_removed", Url: "https://removed.example.com/v1/"}},
+			// Apps without url cannot be routed.
+			{App: &apiv1.App{Id: "app_service_worker", Type: "service", Name: "worker"}},
+		},
+	}
+
+	hosts, routing := d.hostsRouting(info)
+
+	if got := strings.Join(hosts, ","); got != "api.test.local.test,outblocks.host.test,removed.example.com.test,test.local.test" {
+		t.Errorf("unexpected hosts: %s", got)
+	}
+
+	checkRouting(t, routing, map[string]string{
+		"http://api.test.local.test:8000/":         "http://127.0.0.1:8001",
+		"http://test.local.test:8000/payments/":    "https://staging.example.com/payments/",
+		"http://removed.example.com.test:8000/v1/": "https://removed.example.com/v1/",
+	})
+}
+
+func TestRunHostsRoutingTargetedApps(t *testing.T) {
+	t.Parallel()
+
+	// Only targeted app is loaded, other apps are known only from remote state.
+	d := NewRun(logger.NewLogger(), loadRunRoutingProject(t, "api"), &RunOptions{
+		HostsRouting: true,
+		HostsSuffix:  ".test",
+		ListenPort:   8000,
+	})
+
+	info := &runInfo{
+		apps: []*apiv1.AppRun{
+			{App: &apiv1.App{Id: "app_service_api"}, Url: "http://api.test.local.test:8000/", Ip: "127.0.0.1", Port: 8001},
+		},
+		remoteApps: []*apiv1.AppState{
+			{App: &apiv1.App{Id: "app_service_payments", Url: "https://staging.example.com/payments/"}},
+		},
+	}
+
+	hosts, routing := d.hostsRouting(info)
+
+	if got := strings.Join(hosts, ","); got != "api.test.local.test,outblocks.host.test,staging.example.com.test" {
+		t.Errorf("unexpected hosts: %s", got)
+	}
+
+	checkRouting(t, routing, map[string]string{
+		"http://api.test.local.test:8000/":               "http://127.0.0.1:8001",
+		"http://staging.example.com.test:8000/payments/": "https://staging.example.com/payments/",
+	})
+}
+
+func TestRunHTTPServerRemotePath(t *testing.T) {
+	t.Parallel()
+
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(w, "%s%s", req.Host, req.URL.Path)
+	}))
+	defer remote.Close()
+
+	target, _ := url.Parse(remote.URL)
+	// Use hostname other than loopback IP so that target is treated as remote one.
+	target.Host = strings.Replace(target.Host, loopbackIP, "localhost", 1)
+	target.Path = "/v2/"
+
+	d := NewRun(logger.NewLogger(), nil, &RunOptions{})
+	srv := httptest.NewServer(d.newHTTPServer(map[*url.URL]*url.URL{
+		{Host: "app.test", Path: "/payments/"}: target,
+	}).Handler)
+
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/payments/charges", http.NoBody)
+	req.Host = "app.test"
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if got, want := string(body), target.Host+"/v2/charges"; got != want {
+		t.Errorf("unexpected remote request: %q, want %q", got, want)
+	}
+}
+
+func checkRouting(t *testing.T, routing map[*url.URL]*url.URL, want map[string]string) {
+	t.Helper()
+
+	got := make(map[string]string, len(routing))
+
+	for k, v := range routing {
+		got[k.String()] = v.String()
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("unexpected routing: %v", got)
+	}
+
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("unexpected routing of %s: %q, want %q", k, got[k], v)
+		}
+	}
+}
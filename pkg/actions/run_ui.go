@@ -0,0 +1,478 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/outblocks/outblocks-cli/internal/util"
+	"github.com/outblocks/outblocks-cli/pkg/actions/run"
+	"github.com/outblocks/outblocks-cli/pkg/config"
+	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
+)
+
+const (
+	dashboardStateStarting   = "starting"
+	dashboardStateReady      = "ready"
+	dashboardStateRestarting = "restarting"
+	dashboardStateCrashed    = "crashed"
+	dashboardStateExited     = "exited"
+
+	dashboardLogLines     = 1000
+	dashboardRequestLines = 100
+	dashboardRefresh      = 250 * time.Millisecond
+)
+
+var (
+	dashboardHeaderStyle   = lipgloss.NewStyle().Bold(true)
+	dashboardSelectedStyle = lipgloss.NewStyle().Reverse(true)
+	dashboardHelpStyle     = lipgloss.NewStyle().Faint(true)
+	dashboardStderrStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	dashboardStateStyles   = map[string]lipgloss.Style{
+		dashboardStateStarting:   lipgloss.NewStyle().Foreground(lipgloss.Color("3")),
+		dashboardStateRestarting: lipgloss.NewStyle().Foreground(lipgloss.Color("3")),
+		dashboardStateReady:      lipgloss.NewStyle().Foreground(lipgloss.Color("2")),
+		dashboardStateCrashed:    lipgloss.NewStyle().Foreground(lipgloss.Color("1")),
+		dashboardStateExited:     lipgloss.NewStyle().Foreground(lipgloss.Color("1")),
+	}
+)
+
+type dashboardLogLine struct {
+	msg    string
+	stderr bool
+}
+
+type dashboardItem struct {
+	id, name, url string
+	port          int32
+	app           *apiv1.AppRun
+
+	// Set only for apps run directly, those can be restarted.
+	local *run.LocalRunResult
+	info  *run.LocalAppRunInfo
+
+	state    string
+	restarts int
+	logs     []dashboardLogLine
+}
+
+// runDashboard keeps state of all apps and dependencies shown in full-screen dashboard of ok run.
+// It is updated from output and health check goroutines and rendered periodically.
+type runDashboard struct {
+	runner *Run
+	ctx    context.Context
+
+	mu       sync.Mutex
+	items    []*dashboardItem
+	byID     map[string]*dashboardItem
+	requests []string
+	status   string
+}
+
+func newRunDashboard(d *Run, info *runInfo) *runDashboard {
+	dash := &runDashboard{
+		runner: d,
+		byID:   make(map[string]*dashboardItem),
+	}
+
+	for _, app := range info.apps {
+		dash.add(&dashboardItem{
+			id:    app.App.Id,
+			name:  fmt.Sprintf("%s.%s", app.App.Type, app.App.Name),
+			url:   app.Url,
+			port:  app.Port,
+			app:   app,
+			state: dashboardStateStarting,
+		})
+	}
+
+	for _, dep := range info.deps {
+		dash.add(&dashboardItem{
+			id:    config.ComputeDependencyID(dep.Dependency.Name),
+			name:  fmt.Sprintf("dep.%s", dep.Dependency.Name),
+			port:  dep.Port,
+			state: dashboardStateStarting,
+		})
+	}
+
+	return dash
+}
+
+func (d *runDashboard) add(item *dashboardItem) {
+	d.items = append(d.items, item)
+	d.byID[item.id] = item
+}
+
+func (d *runDashboard) setState(id, state string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if item, ok := d.byID[id]; ok {
+		item.state = state
+	}
+}
+
+func (d *runDashboard) setStatus(format string, a ...interface{}) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.status = fmt.Sprintf(format, a...)
+}
+
+func (d *runDashboard) output(id string, stream apiv1.RunOutputResponse_Stream, msg string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	item, ok := d.byID[id]
+	if !ok {
+		return
+	}
+
+	item.logs = append(item.logs, dashboardLogLine{
+		msg:    msg,
+		stderr: stream == apiv1.RunOutputResponse_STREAM_STDERR,
+	})
+
+	if len(item.logs) > dashboardLogLines {
+		item.logs = item.logs[len(item.logs)-dashboardLogLines:]
+	}
+}
+
+func (d *runDashboard) request(line string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.requests = append(d.requests, line)
+
+	if len(d.requests) > dashboardRequestLines {
+		d.requests = d.requests[len(d.requests)-dashboardRequestLines:]
+	}
+}
+
+// supervise tracks apps run directly so that their exit is shown instead of stopping the whole run.
+func (d *runDashboard) supervise(ctx context.Context, local *run.LocalRunResult) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, item := range d.items {
+		info := local.App(item.id)
+		if info == nil {
+			continue
+		}
+
+		item.local = local
+		item.info = info
+
+		go d.watch(ctx, item, info)
+	}
+}
+
+func (d *runDashboard) watch(ctx context.Context, item *dashboardItem, info *run.LocalAppRunInfo) {
+	err := info.Wait()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// Stopped due to shutdown or restart.
+	if ctx.Err() != nil || item.info != info {
+		return
+	}
+
+	if err != nil {
+		item.state = dashboardStateCrashed
+		d.status = fmt.Sprintf("App %s crashed: %s", item.name, err)
+
+		return
+	}
+
+	item.state = dashboardStateExited
+	d.status = fmt.Sprintf("App %s exited", item.name)
+}
+
+func (d *runDashboard) restart(item *dashboardItem) {
+	d.mu.Lock()
+
+	if item.local == nil {
+		d.mu.Unlock()
+
+		if item.app == nil {
+			d.setStatus("Dependency %s is run through plugin and cannot be restarted", item.name)
+		} else {
+			d.setStatus("App %s is run through plugin, only apps run directly can be restarted", item.name)
+		}
+
+		return
+	}
+
+	if item.state == dashboardStateRestarting {
+		d.mu.Unlock()
+
+		return
+	}
+
+	item.info = nil
+	item.state = dashboardStateRestarting
+	d.status = fmt.Sprintf("Restarting app %s...", item.name)
+
+	d.mu.Unlock()
+
+	info, err := item.local.Restart(item.id)
+
+	d.mu.Lock()
+
+	item.info = info
+	item.restarts++
+
+	if err != nil {
+		item.state = dashboardStateCrashed
+		d.status = fmt.Sprintf("App %s restart failed: %s", item.name, err)
+
+		d.mu.Unlock()
+
+		return
+	}
+
+	item.state = dashboardStateStarting
+
+	d.mu.Unlock()
+
+	go d.watch(d.ctx, item, info)
+
+	if err := d.runner.waitApp(d.ctx, item.app); err != nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if item.info == info && item.state == dashboardStateStarting {
+		item.state = dashboardStateReady
+		d.status = fmt.Sprintf("App %s restarted", item.name)
+	}
+}
+
+func (d *runDashboard) openURL(item *dashboardItem) {
+	if item.url == "" {
+		d.setStatus("%s has no url", item.name)
+
+		return
+	}
+
+	if err := util.OpenURL(item.url); err != nil {
+		d.setStatus("Cannot open %s: %s", item.url, err)
+
+		return
+	}
+
+	d.setStatus("Opened %s", item.url)
+}
+
+func (d *runDashboard) toggleRequestLog() {
+	if !d.runner.opts.HostsRouting {
+		d.setStatus("Request logging requires hosts routing to be enabled")
+
+		return
+	}
+
+	enabled := !d.runner.requestLog.Load()
+	d.runner.requestLog.Store(enabled)
+
+	if enabled {
+		d.setStatus("Request logging enabled")
+	} else {
+		d.setStatus("Request logging disabled")
+	}
+}
+
+func (d *runDashboard) run(ctx context.Context) error {
+	d.ctx = ctx
+
+	p := tea.NewProgram(&dashboardModel{dash: d}, tea.WithAltScreen(), tea.WithContext(ctx))
+
+	_, err := p.Run()
+	if ctx.Err() != nil || errors.Is(err, tea.ErrProgramKilled) {
+		return nil
+	}
+
+	return err
+}
+
+type dashboardTickMsg struct{}
+
+type dashboardModel struct {
+	dash          *runDashboard
+	selected      int
+	tail          bool
+	width, height int
+}
+
+func dashboardTick() tea.Cmd {
+	return tea.Tick(dashboardRefresh, func(time.Time) tea.Msg {
+		return dashboardTickMsg{}
+	})
+}
+
+func (m *dashboardModel) Init() tea.Cmd {
+	return dashboardTick()
+}
+
+func (m *dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+
+	case dashboardTickMsg:
+		return m, dashboardTick()
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		}
+
+		if len(m.dash.items) == 0 {
+			return m, nil
+		}
+
+		item := m.dash.items[m.selected]
+
+		switch msg.String() {
+		case "up", "k":
+			if m.selected > 0 {
+				m.selected--
+			}
+		case "down", "j":
+			if m.selected < len(m.dash.items)-1 {
+				m.selected++
+			}
+		case "enter", "l":
+			m.tail = !m.tail
+		case "esc":
+			m.tail = false
+		case "r":
+			go m.dash.restart(item)
+		case "o":
+			go m.dash.openURL(item)
+		case "t":
+			m.dash.toggleRequestLog()
+		}
+	}
+
+	return m, nil
+}
+
+func (m *dashboardModel) View() string {
+	d := m.dash
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	nameWidth, urlWidth := len("NAME"), len("URL")
+
+	for _, item := range d.items {
+		nameWidth = max(nameWidth, len(item.name))
+		urlWidth = max(urlWidth, len(item.url))
+	}
+
+	row := func(name, state, port, url, restarts, last string) string {
+		return fmt.Sprintf("%-*s  %-10s  %-5s  %-*s  %-8s  %s", nameWidth, name, state, port, urlWidth, url, restarts, last)
+	}
+
+	var b strings.Builder
+
+	b.WriteString(dashboardHeaderStyle.Render(m.truncate(row("NAME", "STATE", "PORT", "URL", "RESTARTS", "LAST LOG"))))
+	b.WriteString("\n")
+
+	for i, item := range d.items {
+		var last string
+
+		if len(item.logs) > 0 {
+			last = item.logs[len(item.logs)-1].msg
+		}
+
+		state := item.state
+
+		// Selected row is rendered in reverse as a whole, nested colors would break it.
+		if i != m.selected {
+			state = dashboardStateStyles[item.state].Render(fmt.Sprintf("%-10s", item.state))
+		}
+
+		line := m.truncate(row(item.name, state, fmt.Sprint(item.port), item.url, fmt.Sprint(item.restarts), last))
+
+		if i == m.selected {
+			line = dashboardSelectedStyle.Render(line)
+		}
+
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	// Space left for logs/requests pane below the table and above footer.
+	space := m.height - len(d.items) - 5
+
+	switch {
+	case m.tail && len(d.items) > 0:
+		item := d.items[m.selected]
+
+		b.WriteString("\n")
+		b.WriteString(dashboardHeaderStyle.Render(fmt.Sprintf("Logs of %s", item.name)))
+		b.WriteString("\n")
+
+		for _, l := range lastN(item.logs, space) {
+			msg := m.truncate(l.msg)
+			if l.stderr {
+				msg = dashboardStderrStyle.Render(msg)
+			}
+
+			b.WriteString(msg)
+			b.WriteString("\n")
+		}
+
+	case d.runner.requestLog.Load():
+		b.WriteString("\n")
+		b.WriteString(dashboardHeaderStyle.Render("Requests"))
+		b.WriteString("\n")
+
+		for _, r := range lastN(d.requests, space) {
+			b.WriteString(m.truncate(r))
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n")
+
+	if d.status != "" {
+		b.WriteString(m.truncate(d.status))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(dashboardHelpStyle.Render(m.truncate("↑/↓ select • enter tail logs • r restart • o open url • t toggle request log • q quit")))
+
+	return b.String()
+}
+
+func (m *dashboardModel) truncate(s string) string {
+	if m.width <= 0 {
+		return s
+	}
+
+	return ansi.Truncate(s, m.width, "…")
+}
+
+func lastN[T any](s []T, n int) []T {
+	if n <= 0 {
+		return nil
+	}
+
+	if len(s) > n {
+		return s[len(s)-n:]
+	}
+
+	return s
+}
@@ -0,0 +1,136 @@
+package actions
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/outblocks/outblocks-cli/pkg/logger"
+	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
+)
+
+func newTestDashboard() *runDashboard {
+	d := NewRun(logger.NewLogger(), nil, &RunOptions{})
+
+	return newRunDashboard(d, &runInfo{
+		apps: []*apiv1.AppRun{
+			{App: &apiv1.App{Id: "app_service_api", Type: "service", Name: "api"}, Url: "http://localhost:8001/", Port: 8001},
+			{App: &apiv1.App{Id: "app_static_web", Type: "static", Name: "web"}, Url: "http://localhost:8002/", Port: 8002},
+		},
+		deps: []*apiv1.DependencyRun{depRun("db", 8003)},
+	})
+}
+
+func dashboardKey(k string) tea.KeyMsg {
+	switch k {
+	case "up":
+		return tea.KeyMsg{Type: tea.KeyUp}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	}
+
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+}
+
+func TestDashboardModelUpdate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		keys     []string
+		selected int
+		tail     bool
+		status   string
+	}{
+		{"initial", nil, 0, false, ""},
+		{"select down", []string{"down", "j"}, 2, false, ""},
+		{"select stops at last item", []string{"down", "down", "down", "down"}, 2, false, ""},
+		{"select stops at first item", []string{"down", "up", "k", "up"}, 0, false, ""},
+		{"tail logs", []string{"down", "enter"}, 1, true, ""},
+		{"tail toggled off", []string{"enter", "l"}, 0, false, ""},
+		{"tail closed with esc", []string{"enter", "esc"}, 0, false, ""},
+		{"request log requires hosts routing", []string{"t"}, 0, false, "Request logging requires hosts routing to be enabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &dashboardModel{dash: newTestDashboard()}
+
+			for _, k := range tt.keys {
+				if _, cmd := m.Update(dashboardKey(k)); cmd != nil {
+					t.Fatalf("unexpected command for key %s", k)
+				}
+			}
+
+			if m.selected != tt.selected || m.tail != tt.tail || m.dash.status != tt.status {
+				t.Errorf("unexpected model state: selected=%d tail=%t status=%q", m.selected, m.tail, m.dash.status)
+			}
+		})
+	}
+}
+
+func TestDashboardModelQuit(t *testing.T) {
+	t.Parallel()
+
+	for _, k := range []tea.KeyMsg{dashboardKey("q"), {Type: tea.KeyCtrlC}} {
+		m := &dashboardModel{dash: newTestDashboard()}
+
+		_, cmd := m.Update(k)
+		if cmd == nil {
+			t.Fatalf("expected quit command for key %s", k)
+		}
+
+		if _, ok := cmd().(tea.QuitMsg); !ok {
+			t.Errorf("expected quit message for key %s", k)
+		}
+	}
+}
+
+func TestDashboardModelView(t *testing.T) {
+	t.Parallel()
+
+	m := &dashboardModel{dash: newTestDashboard()}
+
+	m.Update(tea.WindowSizeMsg{Width: 200, Height: 20})
+
+	for i := 0; i < dashboardLogLines+10; i++ {
+		m.dash.output("app_static_web", apiv1.RunOutputResponse_STREAM_STDOUT, fmt.Sprintf("line %d", i))
+	}
+
+	m.dash.output("dep_db", apiv1.RunOutputResponse_STREAM_STDERR, "ready")
+	m.dash.output("unknown", apiv1.RunOutputResponse_STREAM_STDOUT, "dropped")
+	m.dash.setState("dep_db", dashboardStateReady)
+
+	if n := len(m.dash.byID["app_static_web"].logs); n != dashboardLogLines {
+		t.Errorf("expected logs to be capped at %d lines, got %d", dashboardLogLines, n)
+	}
+
+	view := m.View()
+
+	for _, s := range []string{"service.api", "static.web", "dep.db", "http://localhost:8002/", fmt.Sprintf("line %d", dashboardLogLines+9), "ready"} {
+		if !strings.Contains(view, s) {
+			t.Errorf("expected view to contain %q:\n%s", s, view)
+		}
+	}
+
+	m.Update(dashboardKey("down"))
+	m.Update(dashboardKey("enter"))
+
+	view = m.View()
+
+	if !strings.Contains(view, "Logs of static.web") || strings.Contains(view, "line 0\n") {
+		t.Errorf("expected view to tail last logs of selected app:\n%s", view)
+	}
+
+	// Height of 20 leaves space for 12 lines of logs below the table of 3 items.
+	if tail := view[strings.Index(view, "Logs of"):]; strings.Count(tail, "line ") != 12 {
+		t.Errorf("expected logs to fit view height:\n%s", view)
+	}
+}
@@ -2,20 +2,24 @@ package actions
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 
 	"github.com/23doors/go-yaml"
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/ansel1/merry/v2"
+	"github.com/outblocks/outblocks-cli/internal/fileutil"
 	"github.com/outblocks/outblocks-cli/internal/statefile"
 	"github.com/outblocks/outblocks-cli/internal/util"
 	"github.com/outblocks/outblocks-cli/pkg/config"
 	"github.com/outblocks/outblocks-cli/pkg/logger"
 	"github.com/outblocks/outblocks-cli/pkg/plugins"
+	"golang.org/x/term"
 )
 
 type SecretsManager struct {
@@ -31,12 +35,25 @@ func NewSecretsManager(log logger.Logger, cfg *config.Project) *SecretsManager {
 }
 
 func (m *SecretsManager) init(ctx context.Context) error {
-	plugin := m.cfg.Secrets.Plugin()
-	if plugin == nil {
-		return fmt.Errorf("secrets has no supported plugin available")
+	_, err := secretsProvider(ctx, m.cfg)
+
+	return err
+}
+
+// secretsProvider returns started secrets provider of project config.
+func secretsProvider(ctx context.Context, cfg *config.Project) (config.SecretsProvider, error) {
+	provider := cfg.Secrets.Provider()
+	if provider == nil {
+		return nil, fmt.Errorf("secrets has no supported plugin available for env '%s', use a secrets plugin or set secrets.type to '%s'", cfg.Env(), config.SecretsTypeFile)
 	}
 
-	return plugin.Client().Start(ctx)
+	if plugin := cfg.Secrets.Plugin(); plugin != nil {
+		if err := plugin.Client().Start(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return provider, nil
 }
 
 func (m *SecretsManager) View(ctx context.Context) error {
@@ -72,7 +89,7 @@ func (m *SecretsManager) Get(ctx context.Context, key string) error {
 		return err
 	}
 
-	val, ok, err := m.cfg.Secrets.Plugin().Client().GetSecret(ctx, key, m.cfg.Secrets.Type, m.cfg.Secrets.Other)
+	val, ok, err := m.cfg.Secrets.Provider().GetSecret(ctx, key)
 	if err != nil {
 		return err
 	}
@@ -96,7 +113,7 @@ func (m *SecretsManager) Set(ctx context.Context, key, value string) error {
 		return err
 	}
 
-	changed, err := m.cfg.Secrets.Plugin().Client().SetSecret(ctx, key, value, m.cfg.Secrets.Type, m.cfg.Secrets.Other)
+	changed, err := m.cfg.Secrets.Provider().SetSecret(ctx, key, value)
 	if err != nil {
 		return err
 	}
@@ -116,7 +133,7 @@ func (m *SecretsManager) Delete(ctx context.Context, key string) error {
 		return err
 	}
 
-	deleted, err := m.cfg.Secrets.Plugin().Client().DeleteSecret(ctx, key, m.cfg.Secrets.Type, m.cfg.Secrets.Other)
+	deleted, err := m.cfg.Secrets.Provider().DeleteSecret(ctx, key)
 	if err != nil {
 		return err
 	}
@@ -136,7 +153,7 @@ func (m *SecretsManager) Destroy(ctx context.Context, force bool) error {
 		return err
 	}
 
-	vals, err := m.cfg.Secrets.Plugin().Client().GetSecrets(ctx, m.cfg.Secrets.Type, m.cfg.Secrets.Other)
+	vals, err := m.cfg.Secrets.Provider().GetSecrets(ctx)
 	if err != nil {
 		return err
 	}
@@ -161,7 +178,7 @@ func (m *SecretsManager) Destroy(ctx context.Context, force bool) error {
 		}
 	}
 
-	err = m.cfg.Secrets.Plugin().Client().DeleteSecrets(ctx, m.cfg.Secrets.Type, m.cfg.Secrets.Other)
+	err = m.cfg.Secrets.Provider().DeleteSecrets(ctx)
 	if err != nil {
 		return err
 	}
@@ -172,7 +189,7 @@ func (m *SecretsManager) Destroy(ctx context.Context, force bool) error {
 }
 
 func (m *SecretsManager) getSecretsValues(ctx context.Context) (vals map[string]string, err error) {
-	vals, err = m.cfg.Secrets.Plugin().Client().GetSecrets(ctx, m.cfg.Secrets.Type, m.cfg.Secrets.Other)
+	vals, err = m.cfg.Secrets.Provider().GetSecrets(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -333,13 +350,20 @@ func (m *SecretsManager) Edit(ctx context.Context) error {
 		}
 
 		m.log.Infoln("Saving secrets...")
-		err = m.cfg.Secrets.Plugin().Client().ReplaceSecrets(ctx, res, m.cfg.Secrets.Type, m.cfg.Secrets.Other)
+		err = m.cfg.Secrets.Provider().ReplaceSecrets(ctx, res)
 
 		return err
 	}
 }
 
-func (m *SecretsManager) Import(ctx context.Context, f string) error {
+type SecretsImportOptions struct {
+	File   string
+	Format string
+	Prefix string
+}
+
+// Import replaces secrets with ones from file. With prefix set, only secrets within prefix namespace are replaced.
+func (m *SecretsManager) Import(ctx context.Context, opts *SecretsImportOptions) error {
 	err := m.init(ctx)
 	if err != nil {
 		return err
@@ -352,18 +376,37 @@ func (m *SecretsManager) Import(ctx context.Context, f string) error {
 		return err
 	}
 
-	data, err := os.ReadFile(f)
+	data, err := os.ReadFile(opts.File)
 	if err != nil {
 		return merry.Errorf("loading import file error: %w", err)
 	}
 
-	res := make(map[string]string)
+	format := opts.Format
+	if format == "" {
+		format = secretsFormatFromPath(opts.File)
+	}
 
-	err = yaml.Unmarshal(data, &res)
+	res, err := parseSecrets(data, format)
 	if err != nil {
 		return merry.Errorf("loading import file error:\n%w", err)
 	}
 
+	if opts.Prefix != "" {
+		res = prefixSecrets(res, opts.Prefix)
+
+		// Keep secrets outside of prefix namespace as they are.
+		current, err := m.cfg.Secrets.Provider().GetSecrets(ctx)
+		if err != nil {
+			return err
+		}
+
+		for k, v := range current {
+			if _, ok := res[k]; !ok && !strings.HasPrefix(k, opts.Prefix) {
+				res[k] = v
+			}
+		}
+	}
+
 	d, err := statefile.NewMapDiff(vals, res, 1)
 	if err != nil {
 		return merry.Errorf("computing changes error: %w", err)
@@ -376,7 +419,91 @@ func (m *SecretsManager) Import(ctx context.Context, f string) error {
 	}
 
 	m.log.Infoln("Saving secrets...")
-	err = m.cfg.Secrets.Plugin().Client().ReplaceSecrets(ctx, res, m.cfg.Secrets.Type, m.cfg.Secrets.Other)
+	err = m.cfg.Secrets.Provider().ReplaceSecrets(ctx, res)
 
 	return err
 }
+
+type SecretsExportOptions struct {
+	File   string
+	Format string
+	Prefix string
+	Force  bool
+}
+
+// Export writes secrets to file or stdout. Writing to a terminal requires force so that secrets are not revealed by accident.
+func (m *SecretsManager) Export(ctx context.Context, opts *SecretsExportOptions) error {
+	err := m.init(ctx)
+	if err != nil {
+		return err
+	}
+
+	format := opts.Format
+	if format == "" {
+		format = SecretsFormatYAML
+
+		if opts.File != "" {
+			format = secretsFormatFromPath(opts.File)
+		}
+	}
+
+	if opts.File == "" && term.IsTerminal(int(os.Stdout.Fd())) && !opts.Force {
+		return merry.New("refusing to print secrets to a terminal, redirect output, use --file or --force")
+	}
+
+	vals, err := m.cfg.Secrets.Provider().GetSecrets(ctx)
+	if err != nil {
+		return err
+	}
+
+	if opts.Prefix != "" {
+		vals = unprefixSecrets(vals, opts.Prefix)
+	}
+
+	var buf bytes.Buffer
+
+	if err := writeSecrets(&buf, vals, format); err != nil {
+		return err
+	}
+
+	if opts.File == "" {
+		_, err = buf.WriteTo(os.Stdout)
+
+		return err
+	}
+
+	if err := writeSecretsFile(opts.File, buf.Bytes()); err != nil {
+		return merry.Errorf("cannot write export file: %w", err)
+	}
+
+	m.log.Successf("Exported %d secrets to %s.\n", len(vals), opts.File)
+
+	return nil
+}
+
+// writeSecretsFile writes data to a temp file next to path and renames it, so that existing file is only replaced
+// once secrets are fully written.
+func writeSecretsFile(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(f.Name()) //nolint:errcheck
+
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(f.Name(), path); err != nil {
+		return err
+	}
+
+	return fileutil.ChownToUser(path)
+}
@@ -0,0 +1,114 @@
+package actions
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ansel1/merry/v2"
+	"github.com/outblocks/outblocks-cli/pkg/config"
+)
+
+type secretsCheckResult struct {
+	Missing    []*config.SecretReference
+	Unused     []string
+	Undeclared map[string][]string
+}
+
+// checkSecrets compares secret references and secrets required by plugins with secrets defined in backend.
+func checkSecrets(refs []*config.SecretReference, vals map[string]string, required map[string][]string) *secretsCheckResult {
+	res := &secretsCheckResult{
+		Undeclared: make(map[string][]string),
+	}
+
+	referenced := make(map[string]struct{})
+
+	for _, ref := range refs {
+		referenced[ref.Key] = struct{}{}
+
+		if _, ok := vals[ref.Key]; !ok {
+			res.Missing = append(res.Missing, ref)
+		}
+	}
+
+	for k, plugs := range required {
+		if _, ok := vals[k]; !ok {
+			res.Undeclared[k] = plugs
+		}
+	}
+
+	for k := range vals {
+		_, isRef := referenced[k]
+		_, isReq := required[k]
+
+		if !isRef && !isReq {
+			res.Unused = append(res.Unused, k)
+		}
+	}
+
+	sort.Strings(res.Unused)
+
+	return res
+}
+
+// Check reports references to undefined secrets as errors, unused secrets and secrets required by plugins that are not set as warnings.
+func (m *SecretsManager) Check(ctx context.Context) error {
+	provider, err := secretsProvider(ctx, m.cfg)
+	if err != nil {
+		return err
+	}
+
+	vals, err := provider.GetSecrets(ctx)
+	if err != nil {
+		return err
+	}
+
+	refs, err := m.cfg.SecretReferences()
+	if err != nil {
+		return err
+	}
+
+	required := make(map[string][]string)
+
+	for _, plug := range m.cfg.LoadedPlugins() {
+		for _, s := range plug.Secrets() {
+			required[s.Key] = append(required[s.Key], plug.Name)
+		}
+	}
+
+	res := checkSecrets(refs, vals, required)
+
+	for _, ref := range res.Missing {
+		file := ref.File
+		if rel, err := filepath.Rel(m.cfg.Dir, file); err == nil {
+			file = rel
+		}
+
+		m.log.Errorf("%s:%d:%d: secret '%s' is not defined.\n", file, ref.Line, ref.Column, ref.Key)
+	}
+
+	undeclared := make([]string, 0, len(res.Undeclared))
+
+	for k := range res.Undeclared {
+		undeclared = append(undeclared, k)
+	}
+
+	sort.Strings(undeclared)
+
+	for _, k := range undeclared {
+		m.log.Warnf("Secret '%s' required by plugin %s is not set.\n", k, strings.Join(res.Undeclared[k], ", "))
+	}
+
+	for _, k := range res.Unused {
+		m.log.Warnf("Secret '%s' is never used.\n", k)
+	}
+
+	if len(res.Missing) > 0 {
+		return merry.Errorf("found %d references to undefined secrets", len(res.Missing))
+	}
+
+	m.log.Successf("Checked %d secret references, all secrets are defined.\n", len(refs))
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/outblocks/outblocks-cli/pkg/config"
+	"golang.org/x/exp/slices"
+)
+
+func TestCheckSecrets(t *testing.T) {
+	refs := []*config.SecretReference{
+		{Key: "db_password", File: "project.outblocks.yaml", Line: 3},
+		{Key: "typo", File: "app.outblocks.yaml", Line: 7},
+	}
+	vals := map[string]string{"db_password": "x", "unused": "y", "plugin_key": "z"}
+	required := map[string][]string{"plugin_key": {"gcp"}, "plugin_missing": {"gcp"}}
+
+	res := checkSecrets(refs, vals, required)
+
+	if len(res.Missing) != 1 || res.Missing[0].Key != "typo" {
+		t.Fatalf("unexpected missing secrets: %v", res.Missing)
+	}
+
+	if !slices.Equal(res.Unused, []string{"unused"}) {
+		t.Fatalf("unexpected unused secrets: %v", res.Unused)
+	}
+
+	if len(res.Undeclared) != 1 || res.Undeclared["plugin_missing"] == nil {
+		t.Fatalf("unexpected undeclared secrets: %v", res.Undeclared)
+	}
+}
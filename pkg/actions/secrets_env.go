@@ -0,0 +1,217 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ansel1/merry/v2"
+	"github.com/outblocks/outblocks-cli/pkg/config"
+	"github.com/pterm/pterm"
+)
+
+const (
+	secretsDiffMissing = "missing"
+	secretsDiffExtra   = "extra"
+	secretsDiffChanged = "differs"
+	secretsDiffSame    = "same"
+)
+
+type secretsDiffEntry struct {
+	Key    string
+	Status string
+}
+
+type SecretsCopyOptions struct {
+	Keys      []string
+	Overwrite bool
+}
+
+// diffSecrets compares secrets of target env against source env, keys missing in target come first.
+func diffSecrets(from, to map[string]string) []*secretsDiffEntry {
+	var ret []*secretsDiffEntry
+
+	for k, v := range from {
+		e := &secretsDiffEntry{
+			Key: k,
+		}
+
+		v2, ok := to[k]
+
+		switch {
+		case !ok:
+			e.Status = secretsDiffMissing
+		case v != v2:
+			e.Status = secretsDiffChanged
+		default:
+			e.Status = secretsDiffSame
+		}
+
+		ret = append(ret, e)
+	}
+
+	for k := range to {
+		if _, ok := from[k]; ok {
+			continue
+		}
+
+		ret = append(ret, &secretsDiffEntry{
+			Key:    k,
+			Status: secretsDiffExtra,
+		})
+	}
+
+	order := map[string]int{
+		secretsDiffMissing: 0,
+		secretsDiffExtra:   1,
+		secretsDiffChanged: 2,
+		secretsDiffSame:    3,
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Status != ret[j].Status {
+			return order[ret[i].Status] < order[ret[j].Status]
+		}
+
+		return ret[i].Key < ret[j].Key
+	})
+
+	return ret
+}
+
+func (m *SecretsManager) envSecrets(ctx context.Context, cfg *config.Project) (config.SecretsProvider, map[string]string, error) {
+	provider, err := secretsProvider(ctx, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	vals, err := provider.GetSecrets(ctx)
+	if err != nil {
+		return nil, nil, merry.Errorf("cannot load secrets of env '%s': %w", cfg.Env(), err)
+	}
+
+	if vals == nil {
+		vals = make(map[string]string)
+	}
+
+	return provider, vals, nil
+}
+
+// Diff shows which secrets are missing, extra or differ in target env compared to source env.
+func (m *SecretsManager) Diff(ctx context.Context, from, to *config.Project) error {
+	_, fromVals, err := m.envSecrets(ctx, from)
+	if err != nil {
+		return err
+	}
+
+	_, toVals, err := m.envSecrets(ctx, to)
+	if err != nil {
+		return err
+	}
+
+	entries := diffSecrets(fromVals, toVals)
+
+	data := [][]string{
+		{"Key", "Status"},
+	}
+
+	differences := 0
+
+	for _, e := range entries {
+		var status string
+
+		switch e.Status {
+		case secretsDiffMissing:
+			status = pterm.Red(e.Status)
+		case secretsDiffExtra:
+			status = pterm.Yellow(e.Status)
+		case secretsDiffChanged:
+			status = pterm.Magenta(e.Status)
+		default:
+			status = pterm.Gray(e.Status)
+		}
+
+		if e.Status != secretsDiffSame {
+			differences++
+		}
+
+		data = append(data, []string{e.Key, status})
+	}
+
+	if differences == 0 {
+		m.log.Successf("Secrets of env '%s' and '%s' are the same.\n", from.Env(), to.Env())
+
+		return nil
+	}
+
+	err = m.log.Table().WithHasHeader().WithData(pterm.TableData(data)).Render()
+	if err != nil {
+		return err
+	}
+
+	m.log.Printf("Found %d differences in env '%s' compared to '%s'.\n", differences, to.Env(), from.Env())
+
+	return nil
+}
+
+// Copy copies secrets from source env to target env, existing different values are kept unless overwrite is set.
+func (m *SecretsManager) Copy(ctx context.Context, from, to *config.Project, opts *SecretsCopyOptions) error {
+	_, fromVals, err := m.envSecrets(ctx, from)
+	if err != nil {
+		return err
+	}
+
+	toProvider, toVals, err := m.envSecrets(ctx, to)
+	if err != nil {
+		return err
+	}
+
+	keys := opts.Keys
+
+	if len(keys) == 0 {
+		for k := range fromVals {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if _, ok := fromVals[k]; !ok {
+			return merry.Errorf("secret '%s' not found in env '%s'", k, from.Env())
+		}
+	}
+
+	var copied, skipped int
+
+	for _, k := range keys {
+		cur, exists := toVals[k]
+
+		if exists && cur == fromVals[k] {
+			continue
+		}
+
+		if exists && !opts.Overwrite {
+			m.log.Warnf("Secret '%s' already set in env '%s' with different value, skipping (use --overwrite to replace it).\n", k, to.Env())
+
+			skipped++
+
+			continue
+		}
+
+		if _, err := toProvider.SetSecret(ctx, k, fromVals[k]); err != nil {
+			return merry.Errorf("cannot set secret '%s' in env '%s': %w", k, to.Env(), err)
+		}
+
+		copied++
+	}
+
+	msg := fmt.Sprintf("Copied %d secrets from env '%s' to '%s'", copied, from.Env(), to.Env())
+	if skipped > 0 {
+		msg += fmt.Sprintf(", skipped %d", skipped)
+	}
+
+	m.log.Successf("%s.\n", msg)
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package actions
+
+import "testing"
+
+func TestDiffSecrets(t *testing.T) {
+	from := map[string]string{"a": "1", "b": "2", "c": "3"}
+	to := map[string]string{"b": "2", "c": "x", "d": "4"}
+
+	got := diffSecrets(from, to)
+
+	want := []struct{ key, status string }{
+		{"a", secretsDiffMissing},
+		{"d", secretsDiffExtra},
+		{"c", secretsDiffChanged},
+		{"b", secretsDiffSame},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("unexpected diff length: %d", len(got))
+	}
+
+	for i, w := range want {
+		if got[i].Key != w.key || got[i].Status != w.status {
+			t.Errorf("entry %d: got %s=%s, want %s=%s", i, got[i].Key, got[i].Status, w.key, w.status)
+		}
+	}
+}
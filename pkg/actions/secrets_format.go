@@ -0,0 +1,217 @@
+package actions
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/23doors/go-yaml"
+	"github.com/ansel1/merry/v2"
+)
+
+const (
+	SecretsFormatYAML   = "yaml"
+	SecretsFormatJSON   = "json"
+	SecretsFormatDotenv = "dotenv"
+)
+
+var SecretsFormats = []string{SecretsFormatYAML, SecretsFormatJSON, SecretsFormatDotenv}
+
+// secretsFormatFromPath guesses secrets file format based on its name, defaulting to YAML.
+func secretsFormatFromPath(path string) string {
+	base := strings.ToLower(filepath.Base(path))
+
+	switch {
+	case strings.HasSuffix(base, ".json"):
+		return SecretsFormatJSON
+	case base == ".env" || strings.HasPrefix(base, ".env.") || strings.HasSuffix(base, ".env"):
+		return SecretsFormatDotenv
+	}
+
+	return SecretsFormatYAML
+}
+
+func parseSecrets(data []byte, format string) (map[string]string, error) {
+	switch format {
+	case SecretsFormatDotenv:
+		return parseDotenv(data)
+	case SecretsFormatJSON, SecretsFormatYAML:
+	default:
+		return nil, merry.Errorf("unknown secrets format: %s", format)
+	}
+
+	raw := make(map[string]interface{})
+
+	var err error
+
+	if format == SecretsFormatJSON {
+		err = json.Unmarshal(data, &raw)
+	} else {
+		err = yaml.Unmarshal(data, &raw)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make(map[string]string, len(raw))
+
+	for k, v := range raw {
+		switch val := v.(type) {
+		case string:
+			ret[k] = val
+		case nil:
+			ret[k] = ""
+		case map[string]interface{}, []interface{}:
+			return nil, merry.Errorf("secret '%s' has to be a scalar value", k)
+		default:
+			ret[k] = fmt.Sprint(val)
+		}
+	}
+
+	return ret, nil
+}
+
+// parseDotenv parses KEY=value lines, supporting comments, `export` prefix and single or double quoted values.
+func parseDotenv(data []byte) (map[string]string, error) {
+	ret := make(map[string]string)
+	s := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+
+	for s.Scan() {
+		lineNo++
+
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+
+		k, v, ok := strings.Cut(line, "=")
+		k = strings.TrimSpace(k)
+
+		if !ok || k == "" || strings.ContainsAny(k, " \t") {
+			return nil, merry.Errorf("invalid dotenv line %d: expected KEY=value", lineNo)
+		}
+
+		v = strings.TrimSpace(v)
+
+		switch {
+		case strings.HasPrefix(v, `"`):
+			end := closingQuote(v)
+			if end == -1 {
+				return nil, merry.Errorf("invalid dotenv line %d: unterminated quoted value", lineNo)
+			}
+
+			v = dotenvUnescape(v[1:end])
+		case strings.HasPrefix(v, "'"):
+			end := strings.Index(v[1:], "'")
+			if end == -1 {
+				return nil, merry.Errorf("invalid dotenv line %d: unterminated quoted value", lineNo)
+			}
+
+			v = v[1 : end+1]
+		default:
+			if i := strings.Index(v, " #"); i != -1 {
+				v = strings.TrimSpace(v[:i])
+			}
+		}
+
+		ret[k] = v
+	}
+
+	return ret, s.Err()
+}
+
+// closingQuote returns index of unescaped closing double quote.
+func closingQuote(v string) int {
+	for i := 1; i < len(v); i++ {
+		switch v[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+
+	return -1
+}
+
+func dotenvUnescape(v string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(v); i++ {
+		if v[i] != '\\' || i == len(v)-1 {
+			b.WriteByte(v[i])
+
+			continue
+		}
+
+		i++
+
+		switch v[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		default:
+			b.WriteByte(v[i])
+		}
+	}
+
+	return b.String()
+}
+
+func writeSecrets(w io.Writer, vals map[string]string, format string) error {
+	switch format {
+	case SecretsFormatYAML:
+		if len(vals) == 0 {
+			return nil
+		}
+
+		data, err := yaml.Marshal(vals)
+		if err != nil {
+			return err
+		}
+
+		_, err = w.Write(data)
+
+		return err
+	case SecretsFormatJSON:
+		return writeRunEnv(w, vals, RunEnvFormatJSON)
+	case SecretsFormatDotenv:
+		return writeRunEnv(w, vals, RunEnvFormatDotenv)
+	}
+
+	return merry.Errorf("unknown secrets format: %s", format)
+}
+
+func prefixSecrets(vals map[string]string, prefix string) map[string]string {
+	ret := make(map[string]string, len(vals))
+
+	for k, v := range vals {
+		ret[prefix+k] = v
+	}
+
+	return ret
+}
+
+// unprefixSecrets returns only secrets with given prefix, stripping it.
+func unprefixSecrets(vals map[string]string, prefix string) map[string]string {
+	ret := make(map[string]string)
+
+	for k, v := range vals {
+		if strings.HasPrefix(k, prefix) {
+			ret[strings.TrimPrefix(k, prefix)] = v
+		}
+	}
+
+	return ret
+}
@@ -0,0 +1,105 @@
+package actions
+
+import (
+	"bytes"
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseDotenv(t *testing.T) {
+	data := []byte(`# comment
+export A=1
+B = plain value # trailing comment
+C="quoted \"value\"\nwith newline \$HOME"
+D='single # kept'
+E=
+`)
+
+	got, err := parseDotenv(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"A": "1",
+		"B": "plain value",
+		"C": "quoted \"value\"\nwith newline $HOME",
+		"D": "single # kept",
+		"E": "",
+	}
+
+	if !maps.Equal(got, want) {
+		t.Fatalf("unexpected result:\n got: %q\nwant: %q", got, want)
+	}
+
+	if _, err := parseDotenv([]byte("NOEQUALS")); err == nil {
+		t.Fatal("expected error for invalid line")
+	}
+}
+
+func TestSecretsFormatsRoundTrip(t *testing.T) {
+	vals := map[string]string{
+		"plain":  "value",
+		"quoted": `a "b" $c \d`,
+		"multi":  "line1\nline2",
+		"empty":  "",
+	}
+
+	for _, format := range SecretsFormats {
+		var buf bytes.Buffer
+
+		if err := writeSecrets(&buf, vals, format); err != nil {
+			t.Fatalf("%s: write error: %s", format, err)
+		}
+
+		got, err := parseSecrets(buf.Bytes(), format)
+		if err != nil {
+			t.Fatalf("%s: parse error: %s", format, err)
+		}
+
+		if !maps.Equal(got, vals) {
+			t.Errorf("%s: round trip mismatch:\n got: %q\nwant: %q", format, got, vals)
+		}
+	}
+}
+
+func TestSecretsPrefix(t *testing.T) {
+	vals := map[string]string{"APP_A": "1", "OTHER": "2"}
+
+	got := unprefixSecrets(vals, "APP_")
+	if !maps.Equal(got, map[string]string{"A": "1"}) {
+		t.Fatalf("unexpected unprefixed secrets: %v", got)
+	}
+
+	if !maps.Equal(prefixSecrets(got, "APP_"), map[string]string{"APP_A": "1"}) {
+		t.Fatal("unexpected prefixed secrets")
+	}
+}
+
+func TestWriteSecretsFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "secrets.env")
+
+	if err := os.WriteFile(path, []byte("OLD=1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writeSecretsFile(path, []byte("NEW=2\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "NEW=2\n" {
+		t.Fatalf("unexpected file content: %q, %v", data, err)
+	}
+
+	if fi, err := os.Stat(path); err != nil || fi.Mode().Perm() != 0o600 {
+		t.Fatalf("expected secrets file to be private, got: %v, %v", fi.Mode(), err)
+	}
+
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Fatalf("expected temp file to be removed, got %d entries", len(entries))
+	}
+}
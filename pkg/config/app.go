@@ -10,6 +10,7 @@ import (
 	"github.com/ansel1/merry/v2"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/outblocks/outblocks-cli/internal/fileutil"
+	"github.com/outblocks/outblocks-cli/internal/util"
 	"github.com/outblocks/outblocks-cli/pkg/plugins"
 	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
 	plugin_util "github.com/outblocks/outblocks-plugin-go/util"
@@ -45,11 +46,12 @@ type App interface {
 }
 
 type AppRunInfo struct {
-	Plugin  string                 `json:"plugin,omitempty"`
-	Command *command.StringCommand `json:"command,omitempty"`
-	Port    int                    `json:"port,omitempty"`
-	Env     map[string]string      `json:"env,omitempty"`
-	Other   map[string]interface{} `yaml:",remain" json:"other,omitempty"`
+	Plugin    string                 `json:"plugin,omitempty"`
+	Command   *command.StringCommand `json:"command,omitempty"`
+	Port      int                    `json:"port,omitempty"`
+	Env       map[string]string      `json:"env,omitempty"`
+	DependsOn []string               `json:"depends_on,omitempty"`
+	Other     map[string]interface{} `yaml:",remain" json:"other,omitempty"`
 }
 
 type AppDeployInfo struct {
@@ -199,6 +201,12 @@ func (a *BasicApp) Normalize(cfg *Project) error {
 		return a.YAMLError("$.url", "url is invalid")
 	}
 
+	for i, t := range a.AppRun.DependsOn {
+		if err := util.NewTargetMatcher().Add(t); err != nil {
+			return a.YAMLError(fmt.Sprintf("$.run.depends_on[%d]", i), err.Error())
+		}
+	}
+
 	err = func() error {
 		for name, n := range a.Needs {
 			if n == nil {
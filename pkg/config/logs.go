@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/outblocks/outblocks-cli/internal/util"
+	"golang.org/x/exp/slices"
+)
+
+var LogsSeverities = []string{"debug", "notice", "info", "warn", "error"}
+
+// LogsQuery is a named set of `ok logs` filters that can be shared through project config.
+type LogsQuery struct {
+	Name        string   `json:"-"`
+	Description string   `json:"description,omitempty"`
+	Targets     []string `json:"targets,omitempty"`
+	OnlyApps    bool     `json:"only_apps,omitempty"`
+	Severity    string   `json:"severity,omitempty"`
+	Contains    []string `json:"contains,omitempty"`
+	NotContains []string `json:"not_contains,omitempty"`
+	Filter      string   `json:"filter,omitempty"`
+	Start       string   `json:"start,omitempty"`
+	End         string   `json:"end,omitempty"`
+}
+
+type Logs struct {
+	Queries map[string]*LogsQuery `json:"queries,omitempty"`
+}
+
+func (l *Logs) Normalize(cfg *Project) error {
+	for name, q := range l.Queries {
+		if q == nil {
+			q = &LogsQuery{}
+			l.Queries[name] = q
+		}
+
+		q.Name = name
+		q.Severity = strings.ToLower(q.Severity)
+
+		if q.Severity != "" && !slices.Contains(LogsSeverities, q.Severity) {
+			return cfg.yamlError(fmt.Sprintf("$.logs.queries.%s.severity", name), fmt.Sprintf("unknown severity, options: %s", strings.Join(LogsSeverities, ", ")))
+		}
+
+		for i, t := range q.Targets {
+			if err := util.NewTargetMatcher().Add(t); err != nil {
+				return cfg.yamlError(fmt.Sprintf("$.logs.queries.%s.targets[%d]", name, i), err.Error())
+			}
+		}
+	}
+
+	return nil
+}
+
+func (l *Logs) Query(name string) *LogsQuery {
+	return l.Queries[name]
+}
+
+// SortedQueries returns all defined queries sorted by name.
+func (l *Logs) SortedQueries() []*LogsQuery {
+	queries := make([]*LogsQuery, 0, len(l.Queries))
+
+	for _, q := range l.Queries {
+		queries = append(queries, q)
+	}
+
+	sort.Slice(queries, func(i, j int) bool {
+		return queries[i].Name < queries[j].Name
+	})
+
+	return queries
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/outblocks/outblocks-cli/pkg/lockfile"
+)
+
+func TestLogsQueriesNormalize(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`name: test
+logs:
+  queries:
+    api-errors:
+      description: API errors
+      targets: [service.api]
+      severity: ERROR
+      contains: [timeout]
+      start: 1h
+    all:
+`)
+
+	p, err := LoadProjectConfigData("project.outblocks.yaml", data, nil, essentialProjectKeysMap, &ProjectOptions{Env: "dev"}, &lockfile.Lockfile{})
+	if err != nil {
+		t.Fatalf("unexpected load error: %v", err)
+	}
+
+	if err := p.Normalize(); err != nil {
+		t.Fatalf("unexpected normalize error: %v", err)
+	}
+
+	q := p.Logs.Query("api-errors")
+	if q == nil {
+		t.Fatal("query api-errors not found")
+	}
+
+	if q.Name != "api-errors" || q.Severity != "error" || q.Start != "1h" || len(q.Targets) != 1 || q.Targets[0] != "service.api" {
+		t.Fatalf("unexpected query: %+v", q)
+	}
+
+	queries := p.Logs.SortedQueries()
+	if len(queries) != 2 || queries[0].Name != "all" || queries[1].Name != "api-errors" {
+		t.Fatalf("unexpected sorted queries: %+v", queries)
+	}
+
+	bad := []byte(`name: test
+logs:
+  queries:
+    broken:
+      severity: loud
+`)
+
+	p, err = LoadProjectConfigData("project.outblocks.yaml", bad, nil, essentialProjectKeysMap, &ProjectOptions{Env: "dev"}, &lockfile.Lockfile{})
+	if err != nil {
+		t.Fatalf("unexpected load error: %v", err)
+	}
+
+	if err := p.Normalize(); err == nil {
+		t.Fatal("expected error for unknown severity")
+	}
+}
@@ -3,20 +3,33 @@ package config
 import (
 	"fmt"
 	"net/url"
+	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/outblocks/outblocks-cli/pkg/plugins"
+	"github.com/outblocks/outblocks-cli/pkg/plugins/client"
 )
 
 type Plugin struct {
-	Name    string                 `json:"name"`
-	Version string                 `json:"version"`
-	Source  string                 `json:"source,omitempty"`
-	Other   map[string]interface{} `yaml:"-,remain"`
+	Name     string                 `json:"name"`
+	Version  string                 `json:"version"`
+	Source   string                 `json:"source,omitempty"`
+	Timeouts *PluginTimeouts        `json:"timeouts,omitempty"`
+	Other    map[string]interface{} `yaml:"-,remain"`
 
 	verConstr *semver.Constraints
 	loaded    *plugins.Plugin
 	order     uint
+	timeouts  *client.Timeouts
+}
+
+// PluginTimeouts are durations of plugin RPCs per family, e.g. 30s or 10m, after which they are canceled. 0 disables timeout.
+type PluginTimeouts struct {
+	Plan  string `json:"plan,omitempty"`
+	Apply string `json:"apply,omitempty"`
+	State string `json:"state,omitempty"`
+	Lock  string `json:"lock,omitempty"`
+	Logs  string `json:"logs,omitempty"`
 }
 
 func (p *Plugin) SetLoaded(plug *plugins.Plugin) {
@@ -35,6 +48,49 @@ func (p *Plugin) Order() uint {
 	return p.order
 }
 
+// RPCTimeouts returns configured plugin RPC timeouts merged with defaults.
+func (p *Plugin) RPCTimeouts() client.Timeouts {
+	if p.timeouts == nil {
+		return client.DefaultTimeouts
+	}
+
+	return *p.timeouts
+}
+
+func (p *Plugin) normalizeTimeouts(i int, cfg *Project) error {
+	timeouts := client.DefaultTimeouts
+	p.timeouts = &timeouts
+
+	if p.Timeouts == nil {
+		return nil
+	}
+
+	for _, t := range []struct {
+		key string
+		val string
+		dst *time.Duration
+	}{
+		{"plan", p.Timeouts.Plan, &p.timeouts.Plan},
+		{"apply", p.Timeouts.Apply, &p.timeouts.Apply},
+		{"state", p.Timeouts.State, &p.timeouts.State},
+		{"lock", p.Timeouts.Lock, &p.timeouts.Lock},
+		{"logs", p.Timeouts.Logs, &p.timeouts.Logs},
+	} {
+		if t.val == "" {
+			continue
+		}
+
+		d, err := time.ParseDuration(t.val)
+		if err != nil || d < 0 {
+			return cfg.yamlError(fmt.Sprintf("$.plugins[%d].timeouts.%s", i, t.key), fmt.Sprintf("Plugin.timeouts.%s is not a valid duration, e.g. 30s or 10m", t.key))
+		}
+
+		*t.dst = d
+	}
+
+	return nil
+}
+
 func (p *Plugin) Normalize(i int, cfg *Project) error {
 	var err error
 
@@ -54,6 +110,10 @@ func (p *Plugin) Normalize(i int, cfg *Project) error {
 		p.Source = u.String()
 	}
 
+	if err := p.normalizeTimeouts(i, cfg); err != nil {
+		return err
+	}
+
 	p.order = uint(i)
 
 	return nil
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/outblocks/outblocks-cli/pkg/lockfile"
+	"github.com/outblocks/outblocks-cli/pkg/plugins/client"
+)
+
+func TestPluginTimeouts(t *testing.T) {
+	t.Parallel()
+
+	load := func(t *testing.T, data string) (*Project, error) {
+		t.Helper()
+
+		p, err := LoadProjectConfigData(filepath.Join(t.TempDir(), "project.outblocks.yaml"), []byte(data), nil, nil, &ProjectOptions{Env: "dev"}, &lockfile.Lockfile{})
+		if err != nil {
+			t.Fatalf("unexpected load error: %v", err)
+		}
+
+		return p, p.Normalize()
+	}
+
+	p, err := load(t, `name: test
+plugins:
+  - name: gcp
+    project: abc
+    timeouts:
+      plan: 10m
+      state: "0"
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plug := p.Plugins[0]
+
+	want := client.DefaultTimeouts
+	want.Plan = 10 * time.Minute
+	want.State = 0
+
+	if got := plug.RPCTimeouts(); got != want {
+		t.Fatalf("unexpected timeouts: %+v, want %+v", got, want)
+	}
+
+	if _, ok := plug.Other["timeouts"]; ok || plug.Other["project"] != "abc" {
+		t.Fatalf("timeouts should not be passed to plugin as properties: %v", plug.Other)
+	}
+
+	_, err = load(t, `name: test
+plugins:
+  - name: gcp
+    timeouts:
+      apply: soon
+`)
+	if err == nil || !strings.Contains(err.Error(), "Plugin.timeouts.apply is not a valid duration") {
+		t.Fatalf("expected invalid duration error, got: %v", err)
+	}
+}
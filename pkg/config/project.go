@@ -39,7 +39,7 @@ var (
 		AppTypeService:  {"services"},
 	}
 
-	essentialProjectKeys    = []string{"name", "dependencies", "plugins", "state", "secrets", "defaults.deploy.plugin", "defaults.run.plugin", "defaults.dns.plugin"}
+	essentialProjectKeys    = []string{"name", "dependencies", "plugins", "state", "secrets", "logs", "defaults.deploy.plugin", "defaults.run.plugin", "defaults.dns.plugin"}
 	essentialProjectKeysMap = util.StringArrayToSet(essentialProjectKeys)
 	essentialAppKeys        = []string{"name", "type", "dir", "url", "deploy.plugin", "run.plugin", "dns.plugin"}
 	essentialAppKeysMap     = util.StringArrayToSet(essentialAppKeys)
@@ -96,9 +96,11 @@ type Project struct {
 	Plugins      []*Plugin              `json:"plugins,omitempty"`
 	DNS          []*DNS                 `json:"dns,omitempty"`
 	Monitoring   *Monitoring            `json:"monitoring,omitempty"`
+	Logs         *Logs                  `json:"logs,omitempty"`
 	Defaults     *Defaults              `json:"defaults,omitempty"`
 
 	appsIDMap        map[string]App
+	appSecretRefs    map[string][]*SecretReference
 	dependencyIDMap  map[string]*Dependency
 	env              string
 	loadedPlugins    []*plugins.Plugin
@@ -211,7 +213,18 @@ func (p *Project) LoadApps(mode LoadMode, opts *LoadAppsOptions) error {
 		return nil
 	}
 
-	files := fileutil.FindYAMLFiles(p.Dir, func(f string) bool {
+	files := p.AppFiles()
+
+	if err := p.LoadAppFiles(files, mode, opts); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AppFiles returns paths of all app config files found in project dir.
+func (p *Project) AppFiles() []string {
+	return fileutil.FindYAMLFiles(p.Dir, func(f string) bool {
 		idx := strings.LastIndex(f, ".")
 		if idx == -1 {
 			return false
@@ -231,12 +244,6 @@ func (p *Project) LoadApps(mode LoadMode, opts *LoadAppsOptions) error {
 
 		return strings.HasSuffix(name, AppYAMLNameSuffix)
 	})
-
-	if err := p.LoadAppFiles(files, mode, opts); err != nil {
-		return err
-	}
-
-	return nil
 }
 
 func (p *Project) LoadAppFiles(files []string, mode LoadMode, opts *LoadAppsOptions) error {
@@ -377,6 +384,8 @@ func (p *Project) LoadAppFile(file string, essentialKeys map[string]bool) error
 		return merry.Errorf("application file %s yaml is invalid", file)
 	}
 
+	refs := secretReferencesInNode(n, file)
+
 	_, err = traverseYAMLMapping(n, file, p.env, p.vals, essentialKeys, nil)
 	if err != nil {
 		return err
@@ -417,6 +426,12 @@ func (p *Project) LoadAppFile(file string, essentialKeys map[string]bool) error
 		return merry.Errorf("application with name: '%s' of type: '%s' found more than once\nfile: %s", typ, app.Name(), file)
 	}
 
+	if p.appSecretRefs == nil {
+		p.appSecretRefs = make(map[string][]*SecretReference)
+	}
+
+	p.appSecretRefs[app.ID()] = refs
+
 	return nil
 }
 
@@ -485,7 +500,13 @@ func (p *Project) FindLoadedPlugin(name string) *plugins.Plugin {
 	return p.loadedPluginsMap[name]
 }
 
-func (p *Project) LoadPlugins(ctx context.Context, log logger.Logger, loader *plugins.Loader, hostAddr string) error {
+func (p *Project) LoadPlugins(ctx context.Context, log logger.Logger, loader *plugins.Loader, host plugins.Host) error {
+	return p.LoadPluginsWithActions(ctx, log, loader, host)
+}
+
+// LoadPluginsWithActions loads project plugins but starts only those supporting any of specified actions.
+// With no actions specified, all plugins are started.
+func (p *Project) LoadPluginsWithActions(ctx context.Context, log logger.Logger, loader *plugins.Loader, host plugins.Host, actions ...plugins.Action) error {
 	plugs := make([]*plugins.Plugin, len(p.Plugins))
 	pluginsToDownload := make(map[int]*Plugin)
 
@@ -504,6 +525,22 @@ func (p *Project) LoadPlugins(ctx context.Context, log logger.Logger, loader *pl
 		plugs[i] = plugin
 
 		plug.SetLoaded(plugin)
+
+		if plugin.IsDev() {
+			log.Warnf("Using development plugin '%s' from %s! It is not pinned in lockfile, do not use it for production deployments.\n", plug.Name, plugin.Dir)
+		}
+	}
+
+	if len(pluginsToDownload) != 0 && loader.IsFrozen() {
+		names := make([]string, 0, len(pluginsToDownload))
+
+		for i, plug := range p.Plugins {
+			if _, ok := pluginsToDownload[i]; ok {
+				names = append(names, plug.Name)
+			}
+		}
+
+		return merry.Errorf("%w: plugins not found locally: %s, vendor them with 'ok plugins vendor' or set a plugin mirror", plugins.ErrPluginDownloadFrozen, strings.Join(names, ", "))
 	}
 
 	if len(pluginsToDownload) != 0 {
@@ -543,21 +580,45 @@ func (p *Project) LoadPlugins(ctx context.Context, log logger.Logger, loader *pl
 		}
 	}
 
+	started := make([]*plugins.Plugin, 0, len(plugs))
+
 	for i, plug := range plugs {
 		plug := plug
 		plugConfig := p.Plugins[i]
 		prefix := fmt.Sprintf("$.plugins[%d]", i)
 
-		if err := plug.Prepare(ctx, log, p.env, p.ID(), p.Name, p.Dir, hostAddr, plugConfig.Other, prefix, p.YAMLData()); err != nil {
+		if !pluginHasAnyAction(plug, actions) {
+			continue
+		}
+
+		if err := plug.Prepare(ctx, log, p.env, p.ID(), p.Name, p.Dir, host, plugConfig.Other, prefix, p.YAMLData()); err != nil {
 			return merry.Errorf("error starting plugin '%s': %w", plug.Name, err)
 		}
+
+		plug.Client().SetTimeouts(plugConfig.RPCTimeouts())
+
+		started = append(started, plug)
 	}
 
-	p.SetLoadedPlugins(plugs)
+	p.SetLoadedPlugins(started)
 
 	return nil
 }
 
+func pluginHasAnyAction(plug *plugins.Plugin, actions []plugins.Action) bool {
+	if len(actions) == 0 {
+		return true
+	}
+
+	for _, a := range actions {
+		if plug.HasAction(a) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func downloadPluginWithRetry(ctx context.Context, maxAttempts int, retryDelay time.Duration, download func() (*plugins.Plugin, error)) (*plugins.Plugin, error) {
 	if maxAttempts < 1 {
 		maxAttempts = 1
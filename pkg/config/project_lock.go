@@ -5,9 +5,19 @@ import (
 )
 
 func (p *Project) Lockfile() *lockfile.Lockfile {
-	plugins := make([]*lockfile.Plugin, len(p.loadedPlugins))
-	for i, plug := range p.loadedPlugins {
-		plugins[i] = plug.Locked()
+	plugins := make([]*lockfile.Plugin, 0, len(p.loadedPlugins))
+
+	for _, plug := range p.loadedPlugins {
+		locked := plug.Locked()
+
+		// Dev plugins are never pinned, keep their previous entry so that pinned version and checksums are not lost.
+		if plug.IsDev() {
+			locked = p.lock.PluginByName(plug.Name)
+		}
+
+		if locked != nil {
+			plugins = append(plugins, locked)
+		}
 	}
 
 	return &lockfile.Lockfile{
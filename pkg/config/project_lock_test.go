@@ -0,0 +1,57 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/outblocks/outblocks-cli/pkg/lockfile"
+	"github.com/outblocks/outblocks-cli/pkg/plugins"
+)
+
+func TestLockfileKeepsDevPluginEntry(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	pluginDir := filepath.Join(dir, "cli-plugin-gcp")
+
+	if err := os.Mkdir(pluginDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(pluginDir, "plugin.yaml"), []byte("name: gcp\ncmd:\n  default: ./plugin\nactions: [deploy]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	pinned := &lockfile.Plugin{
+		Name:      "gcp",
+		Version:   semver.MustParse("0.1.2"),
+		Source:    "https://github.com/outblocks/cli-plugin-gcp",
+		Checksums: map[string]string{"linux_amd64": "abc"},
+	}
+
+	p, err := LoadProjectConfigData(filepath.Join(dir, "project.outblocks.yaml"), []byte("name: test\nplugins:\n  - name: gcp\n"), nil, nil, &ProjectOptions{Env: "dev"}, &lockfile.Lockfile{Plugins: []*lockfile.Plugin{pinned}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plug, err := plugins.NewLoader(dir, t.TempDir()).LoadPlugin(context.Background(), "gcp", "file://cli-plugin-gcp", nil, pinned)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p.SetLoadedPlugins([]*plugins.Plugin{plug})
+
+	lock := p.Lockfile()
+	if len(lock.Plugins) != 1 || lock.Plugins[0] != pinned {
+		t.Fatalf("expected pinned entry of dev plugin to be kept, got: %+v", lock.Plugins)
+	}
+
+	p.lock = nil
+
+	if lock := p.Lockfile(); len(lock.Plugins) != 0 {
+		t.Fatalf("expected dev plugin without previous entry not to be pinned, got: %+v", lock.Plugins)
+	}
+}
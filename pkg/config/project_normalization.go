@@ -84,6 +84,14 @@ func (p *Project) Normalize() error {
 			return err
 		}
 
+		if p.Logs == nil {
+			p.Logs = &Logs{}
+		}
+
+		if err := p.Logs.Normalize(p); err != nil {
+			return err
+		}
+
 		return nil
 	}()
 
@@ -100,6 +108,10 @@ func (p *Project) Normalize() error {
 				return err
 			}
 
+			if err := p.Secrets.checkAppReferences(p, app, p.appSecretRefs[app.ID()]); err != nil {
+				return err
+			}
+
 			if app.URL() != nil {
 				url := app.URL().String()
 				if cur, ok := urlMap[url]; ok {
@@ -193,6 +205,23 @@ func (p *Project) FullCheck() error {
 	return err
 }
 
+// EssentialCheck validates only state and secrets config, e.g. when not all plugins were started.
+func (p *Project) EssentialCheck() error {
+	err := func() error {
+		if err := p.State.Check(p); err != nil {
+			return err
+		}
+
+		return p.Secrets.Check(p)
+	}()
+
+	if err != nil {
+		return merry.Errorf("project config check failed.\nfile: %s\n%s", p.yamlPath, err)
+	}
+
+	return nil
+}
+
 func (p *Project) yamlError(path, msg string) error {
 	return fileutil.YAMLError(path, msg, p.yamlData)
 }
@@ -1,25 +1,59 @@
 package config
 
 import (
+	"context"
 	"strings"
 
 	"github.com/outblocks/outblocks-cli/pkg/plugins"
 )
 
+const SecretsTypeFile = "file"
+
+// SecretsProvider is a backend that stores secrets, either through a plugin or built-in one.
+type SecretsProvider interface {
+	Name() string
+	GetSecret(ctx context.Context, key string) (val string, ok bool, err error)
+	GetSecrets(ctx context.Context) (map[string]string, error)
+	SetSecret(ctx context.Context, key, value string) (changed bool, err error)
+	DeleteSecret(ctx context.Context, key string) (deleted bool, err error)
+	DeleteSecrets(ctx context.Context) error
+	ReplaceSecrets(ctx context.Context, values map[string]string) error
+}
+
 type Secrets struct {
-	Type  string                 `json:"type"`
-	Other map[string]interface{} `yaml:"-,remain"`
+	Type   string                 `json:"type"`
+	Scopes map[string][]string    `json:"scopes,omitempty"`
+	Other  map[string]interface{} `yaml:"-,remain"`
 
 	plugin *plugins.Plugin
+	file   *FileSecrets
+	scopes []*secretsScope
 }
 
 func (s *Secrets) Normalize(cfg *Project) error {
 	s.Type = strings.ToLower(s.Type)
 
+	if err := s.normalizeScopes(cfg); err != nil {
+		return err
+	}
+
+	if s.Type == SecretsTypeFile {
+		var err error
+
+		s.file, err = newFileSecrets(cfg, s.Other)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 func (s *Secrets) Check(cfg *Project) error {
+	if s.Type == SecretsTypeFile {
+		return nil
+	}
+
 	// Check plugin.
 	for _, plug := range cfg.loadedPlugins {
 		if !plug.HasAction(plugins.ActionSecrets) {
@@ -39,3 +73,48 @@ func (s *Secrets) Check(cfg *Project) error {
 func (s *Secrets) Plugin() *plugins.Plugin {
 	return s.plugin
 }
+
+// Provider returns configured secrets backend or nil if there is none available.
+func (s *Secrets) Provider() SecretsProvider {
+	if s.file != nil {
+		return s.file
+	}
+
+	if s.plugin != nil {
+		return &pluginSecrets{s: s}
+	}
+
+	return nil
+}
+
+type pluginSecrets struct {
+	s *Secrets
+}
+
+func (p *pluginSecrets) Name() string {
+	return "plugin: " + p.s.plugin.Name
+}
+
+func (p *pluginSecrets) GetSecret(ctx context.Context, key string) (val string, ok bool, err error) {
+	return p.s.plugin.Client().GetSecret(ctx, key, p.s.Type, p.s.Other)
+}
+
+func (p *pluginSecrets) GetSecrets(ctx context.Context) (map[string]string, error) {
+	return p.s.plugin.Client().GetSecrets(ctx, p.s.Type, p.s.Other)
+}
+
+func (p *pluginSecrets) SetSecret(ctx context.Context, key, value string) (changed bool, err error) {
+	return p.s.plugin.Client().SetSecret(ctx, key, value, p.s.Type, p.s.Other)
+}
+
+func (p *pluginSecrets) DeleteSecret(ctx context.Context, key string) (deleted bool, err error) {
+	return p.s.plugin.Client().DeleteSecret(ctx, key, p.s.Type, p.s.Other)
+}
+
+func (p *pluginSecrets) DeleteSecrets(ctx context.Context) error {
+	return p.s.plugin.Client().DeleteSecrets(ctx, p.s.Type, p.s.Other)
+}
+
+func (p *pluginSecrets) ReplaceSecrets(ctx context.Context, values map[string]string) error {
+	return p.s.plugin.Client().ReplaceSecrets(ctx, values, p.s.Type, p.s.Other)
+}
@@ -0,0 +1,260 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"filippo.io/age"
+	"github.com/ansel1/merry/v2"
+	"github.com/outblocks/outblocks-cli/internal/secretsfile"
+	"github.com/outblocks/outblocks-cli/pkg/clipath"
+	"github.com/outblocks/outblocks-cli/pkg/logger"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	SecretsFileSuffix = ".secrets.enc.yaml"
+	SecretsKeyFile    = "secrets.key"
+
+	// SecretsKeyEnvVar allows to pass identity directly, e.g. in CI.
+	SecretsKeyEnvVar = "OUTBLOCKS_SECRETS_KEY"
+)
+
+// FileSecrets is a built-in secrets backend storing secrets in age encrypted file that is safe to commit.
+type FileSecrets struct {
+	Path       string
+	KeyFile    string
+	Recipients []string
+
+	log logger.Logger
+	mu  sync.Mutex
+	ids []age.Identity
+}
+
+func newFileSecrets(cfg *Project, props map[string]interface{}) (*FileSecrets, error) {
+	f := &FileSecrets{
+		Path:    filepath.Join(cfg.Dir, cfg.env+SecretsFileSuffix),
+		KeyFile: clipath.ConfigDir(SecretsKeyFile),
+		log:     logger.NewLogger(),
+	}
+
+	if v, ok := props["path"]; ok {
+		s, ok := v.(string)
+		if !ok || s == "" {
+			return nil, cfg.yamlError("$.secrets.path", "secrets.path has to be a non-empty string")
+		}
+
+		f.Path = s
+
+		if !filepath.IsAbs(s) {
+			f.Path = filepath.Join(cfg.Dir, s)
+		}
+	}
+
+	if v, ok := props["key_file"]; ok {
+		s, ok := v.(string)
+		if !ok || s == "" {
+			return nil, cfg.yamlError("$.secrets.key_file", "secrets.key_file has to be a non-empty string")
+		}
+
+		f.KeyFile = s
+
+		if !filepath.IsAbs(s) {
+			f.KeyFile = filepath.Join(cfg.Dir, s)
+		}
+	}
+
+	if v, ok := props["recipients"]; ok {
+		list, ok := v.([]interface{})
+		if !ok {
+			return nil, cfg.yamlError("$.secrets.recipients", "secrets.recipients has to be a list of age public keys")
+		}
+
+		for i, r := range list {
+			s, ok := r.(string)
+			if !ok {
+				return nil, cfg.yamlError(fmt.Sprintf("$.secrets.recipients[%d]", i), "recipient has to be an age public key")
+			}
+
+			if _, err := age.ParseX25519Recipient(s); err != nil {
+				return nil, cfg.yamlError(fmt.Sprintf("$.secrets.recipients[%d]", i), fmt.Sprintf("invalid recipient: %s", err))
+			}
+
+			f.Recipients = append(f.Recipients, s)
+		}
+	}
+
+	return f, nil
+}
+
+func (f *FileSecrets) Name() string {
+	return "file: " + f.Path
+}
+
+// identities loads identities from env or key file, generating new key file if needed and allowed.
+func (f *FileSecrets) identities(generate bool) ([]age.Identity, error) {
+	if f.ids != nil {
+		return f.ids, nil
+	}
+
+	if key := os.Getenv(SecretsKeyEnvVar); key != "" {
+		ids, err := age.ParseIdentities(strings.NewReader(key))
+		if err != nil {
+			return nil, merry.Errorf("cannot parse secrets key from %s: %w", SecretsKeyEnvVar, err)
+		}
+
+		f.ids = ids
+
+		return ids, nil
+	}
+
+	ids, err := secretsfile.LoadIdentities(f.KeyFile)
+	if err == nil {
+		f.ids = ids
+
+		return ids, nil
+	}
+
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	if !generate {
+		return nil, merry.Errorf("secrets key file %s not found, generate one by setting a secret or ask for your public key to be added to secrets.recipients", f.KeyFile)
+	}
+
+	id, err := secretsfile.GenerateIdentity(f.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	f.log.Infof("Generated new secrets key: %s\nYour public key: %s\n", f.KeyFile, id.Recipient())
+
+	f.ids = []age.Identity{id}
+
+	return f.ids, nil
+}
+
+func (f *FileSecrets) load() (map[string]string, error) {
+	if _, err := os.Stat(f.Path); errors.Is(err, os.ErrNotExist) {
+		return make(map[string]string), nil
+	}
+
+	ids, err := f.identities(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return secretsfile.Load(f.Path, ids)
+}
+
+func (f *FileSecrets) save(vals map[string]string) error {
+	ids, err := f.identities(true)
+	if err != nil {
+		return err
+	}
+
+	own := secretsfile.IdentityRecipients(ids)
+	recipients := f.Recipients
+
+	if len(recipients) == 0 {
+		recipients = own
+	} else {
+		found := false
+
+		for _, r := range own {
+			if slices.Contains(recipients, r) {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return merry.Errorf("none of your public keys (%s) is listed in secrets.recipients, you would not be able to decrypt secrets", strings.Join(own, ", "))
+		}
+	}
+
+	return secretsfile.Save(f.Path, vals, append([]string(nil), recipients...))
+}
+
+func (f *FileSecrets) GetSecret(_ context.Context, key string) (val string, ok bool, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	vals, err := f.load()
+	if err != nil {
+		return "", false, err
+	}
+
+	val, ok = vals[key]
+
+	return val, ok, nil
+}
+
+func (f *FileSecrets) GetSecrets(_ context.Context) (map[string]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.load()
+}
+
+func (f *FileSecrets) SetSecret(_ context.Context, key, value string) (changed bool, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	vals, err := f.load()
+	if err != nil {
+		return false, err
+	}
+
+	if cur, ok := vals[key]; ok && cur == value {
+		return false, nil
+	}
+
+	vals[key] = value
+
+	return true, f.save(vals)
+}
+
+func (f *FileSecrets) DeleteSecret(_ context.Context, key string) (deleted bool, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	vals, err := f.load()
+	if err != nil {
+		return false, err
+	}
+
+	if _, ok := vals[key]; !ok {
+		return false, nil
+	}
+
+	delete(vals, key)
+
+	return true, f.save(vals)
+}
+
+func (f *FileSecrets) DeleteSecrets(_ context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	err := os.Remove(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+func (f *FileSecrets) ReplaceSecrets(_ context.Context, values map[string]string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.save(values)
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/outblocks/outblocks-cli/pkg/logger"
+)
+
+func TestFileSecretsRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	t.Setenv(SecretsKeyEnvVar, "")
+
+	f := &FileSecrets{
+		Path:    filepath.Join(dir, "dev"+SecretsFileSuffix),
+		KeyFile: filepath.Join(dir, "keys", SecretsKeyFile),
+		log:     logger.NewLogger(),
+	}
+
+	vals, err := f.GetSecrets(ctx)
+	if err != nil || len(vals) != 0 {
+		t.Fatalf("expected empty secrets without file, got: %v, %v", vals, err)
+	}
+
+	changed, err := f.SetSecret(ctx, "db_password", "s3cret")
+	if err != nil || !changed {
+		t.Fatalf("unexpected set result: %v, %v", changed, err)
+	}
+
+	if _, err := os.Stat(f.KeyFile); err != nil {
+		t.Fatalf("expected key file to be generated: %s", err)
+	}
+
+	if st, err := os.Stat(f.Path); err != nil || st.Mode().Perm() != 0o600 {
+		t.Fatalf("expected secrets file readable only by owner: %v, %v", st, err)
+	}
+
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(data), "s3cret") {
+		t.Fatal("secrets file contains plain text value")
+	}
+
+	// Fresh instance reads key file from disk.
+	f2 := &FileSecrets{Path: f.Path, KeyFile: f.KeyFile, log: f.log}
+
+	val, ok, err := f2.GetSecret(ctx, "db_password")
+	if err != nil || !ok || val != "s3cret" {
+		t.Fatalf("unexpected get result: %q, %v, %v", val, ok, err)
+	}
+
+	changed, err = f2.SetSecret(ctx, "db_password", "s3cret")
+	if err != nil || changed {
+		t.Fatalf("expected unchanged secret: %v, %v", changed, err)
+	}
+
+	deleted, err := f2.DeleteSecret(ctx, "db_password")
+	if err != nil || !deleted {
+		t.Fatalf("unexpected delete result: %v, %v", deleted, err)
+	}
+
+	// Key that is not a recipient cannot decrypt.
+	other, _ := age.GenerateX25519Identity()
+	t.Setenv(SecretsKeyEnvVar, other.String())
+
+	_ = f2.ReplaceSecrets(ctx, map[string]string{"a": "b"})
+
+	f3 := &FileSecrets{Path: f.Path, KeyFile: f.KeyFile, log: f.log}
+
+	if _, err := f3.GetSecrets(ctx); err == nil {
+		t.Fatal("expected decryption error for key that is not a recipient")
+	}
+
+	// Refuse to encrypt when own key is not among configured recipients.
+	f3.Recipients = []string{f2.ids[0].(*age.X25519Identity).Recipient().String()}
+
+	if err := f3.ReplaceSecrets(ctx, map[string]string{"a": "b"}); err == nil {
+		t.Fatal("expected error when own key is not a recipient")
+	}
+}
+
+func TestFileSecretsPaths(t *testing.T) {
+	dir := t.TempDir()
+	abs := filepath.Join(t.TempDir(), "shared", "secrets.age")
+	cfg := &Project{Dir: dir, env: "dev"}
+
+	f, err := newFileSecrets(cfg, map[string]interface{}{"path": abs, "key_file": "keys/key.txt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f.Path != abs || f.KeyFile != filepath.Join(dir, "keys", "key.txt") {
+		t.Fatalf("unexpected paths: %s, %s", f.Path, f.KeyFile)
+	}
+
+	f, err = newFileSecrets(cfg, map[string]interface{}{"path": "secrets/dev.age"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f.Path != filepath.Join(dir, "secrets", "dev.age") {
+		t.Fatalf("expected relative path to be resolved against project dir, got: %s", f.Path)
+	}
+}
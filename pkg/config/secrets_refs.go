@@ -0,0 +1,113 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/23doors/go-yaml/ast"
+	"github.com/23doors/go-yaml/parser"
+	"github.com/ansel1/merry/v2"
+	plugin_util "github.com/outblocks/outblocks-plugin-go/util"
+)
+
+const secretsVarPrefix = "secrets."
+
+// SecretReference is a single `${secrets.<key>}` usage in project or app config.
+type SecretReference struct {
+	Key    string
+	File   string
+	Line   int
+	Column int
+}
+
+// location returns file:line:column of reference with file relative to dir.
+func (r *SecretReference) location(dir string) string {
+	file := r.File
+	if rel, err := filepath.Rel(dir, file); err == nil {
+		file = rel
+	}
+
+	return fmt.Sprintf("%s:%d:%d", file, r.Line, r.Column)
+}
+
+// SecretReferences collects references to secrets in project and app config files.
+func (p *Project) SecretReferences() ([]*SecretReference, error) {
+	files := append([]string{p.yamlPath}, p.AppFiles()...)
+
+	var refs []*SecretReference
+
+	for _, f := range files {
+		fileRefs, err := secretReferencesInFile(f)
+		if err != nil {
+			return nil, err
+		}
+
+		refs = append(refs, fileRefs...)
+	}
+
+	sort.SliceStable(refs, func(i, j int) bool {
+		return refs[i].Key < refs[j].Key
+	})
+
+	return refs, nil
+}
+
+func secretReferencesInFile(file string) ([]*SecretReference, error) {
+	f, err := parser.ParseFile(file, 0)
+	if err != nil {
+		return nil, merry.Errorf("cannot read yaml file: %s, cause: %w", file, err)
+	}
+
+	var refs []*SecretReference
+
+	for _, doc := range f.Docs {
+		refs = append(refs, secretReferencesInNode(doc.Body, file)...)
+	}
+
+	return refs, nil
+}
+
+func secretReferencesInNode(node ast.Node, file string) []*SecretReference {
+	var refs []*SecretReference
+
+	_, _ = walkYAMLMapping(node, nil, func(n *ast.StringNode, _ []string) (ast.Node, error) {
+		pos := n.GetToken().Position
+
+		for _, key := range secretKeysInString(n.Value) {
+			refs = append(refs, &SecretReference{
+				Key:    key,
+				File:   file,
+				Line:   pos.Line,
+				Column: pos.Column,
+			})
+		}
+
+		return n, nil
+	})
+
+	return refs
+}
+
+// secretKeysInString returns secret keys referenced in value, parsing it the same way as during config expansion.
+func secretKeysInString(val string) []string {
+	if !strings.Contains(val, "${") {
+		return nil
+	}
+
+	var keys []string
+
+	_, _, _ = plugin_util.NewBaseVarEvaluator(nil).
+		WithKeyGetter(func(c *plugin_util.VarContext, _ map[string]interface{}) (interface{}, error) {
+			if strings.HasPrefix(c.Token, secretsVarPrefix) {
+				keys = append(keys, strings.TrimPrefix(c.Token, secretsVarPrefix))
+			}
+
+			return "", nil
+		}).
+		WithSkipRowColumnInfo(true).
+		WithVarChar('$').ExpandRaw([]byte(val))
+
+	return keys
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"sync"
+
+	"github.com/23doors/go-yaml/parser"
+	"github.com/ansel1/merry/v2"
+	"github.com/outblocks/outblocks-cli/internal/util"
+)
+
+// secretsScope allows apps matching target to reference secret keys matching any of patterns.
+type secretsScope struct {
+	target   string
+	patterns []string
+
+	// Matcher counts matches, it is guarded as secrets filter is called from concurrent host server calls.
+	mu      sync.Mutex
+	matcher *util.TargetMatcher
+}
+
+func (sc *secretsScope) matches(appID string) bool {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	return sc.matcher.Matches(appID)
+}
+
+func (s *Secrets) normalizeScopes(cfg *Project) error {
+	s.scopes = nil
+
+	targets := make([]string, 0, len(s.Scopes))
+
+	for t := range s.Scopes {
+		targets = append(targets, t)
+	}
+
+	sort.Strings(targets)
+
+	for _, t := range targets {
+		matcher := util.NewTargetMatcher()
+
+		if err := matcher.AddApp(t); err != nil {
+			return cfg.yamlError(fmt.Sprintf("$.secrets.scopes.%s", t), err.Error())
+		}
+
+		for i, p := range s.Scopes[t] {
+			if _, err := path.Match(p, ""); err != nil {
+				return cfg.yamlError(fmt.Sprintf("$.secrets.scopes.%s[%d]", t, i), fmt.Sprintf("invalid secret key pattern '%s'", p))
+			}
+		}
+
+		s.scopes = append(s.scopes, &secretsScope{
+			target:   t,
+			matcher:  matcher,
+			patterns: s.Scopes[t],
+		})
+	}
+
+	if len(s.scopes) == 0 {
+		return nil
+	}
+
+	// Scoped secrets cannot be referenced outside of app configs.
+	f, err := parser.ParseBytes(cfg.yamlData, 0)
+	if err != nil {
+		return merry.Errorf("cannot read project yaml file: %s, cause: %w", cfg.yamlPath, err)
+	}
+
+	for _, doc := range f.Docs {
+		for _, ref := range secretReferencesInNode(doc.Body, cfg.yamlPath) {
+			if s.IsScoped(ref.Key) {
+				return merry.Errorf("secret '%s' is scoped to apps and cannot be referenced in project config\nfile: %s", ref.Key, ref.location(cfg.Dir))
+			}
+		}
+	}
+
+	return nil
+}
+
+func matchesSecretPatterns(patterns []string, key string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, key); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsScoped returns true if key is restricted to apps by secrets scopes.
+func (s *Secrets) IsScoped(key string) bool {
+	for _, sc := range s.scopes {
+		if matchesSecretPatterns(sc.patterns, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsAllowed returns true if app with given ID may access secret key.
+// Keys not matched by any scope are available to all apps.
+func (s *Secrets) IsAllowed(appID, key string) bool {
+	if !s.IsScoped(key) {
+		return true
+	}
+
+	if appID == "" {
+		return false
+	}
+
+	for _, sc := range s.scopes {
+		if matchesSecretPatterns(sc.patterns, key) && sc.matches(appID) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Secrets) checkAppReferences(cfg *Project, app App, refs []*SecretReference) error {
+	for _, ref := range refs {
+		if s.IsAllowed(app.ID(), ref.Key) {
+			continue
+		}
+
+		return merry.Errorf("secret '%s' is not in scope of %s app '%s', add it to secrets.scopes to allow it\nfile: %s", ref.Key, app.Type(), app.Name(), ref.location(cfg.Dir))
+	}
+
+	return nil
+}
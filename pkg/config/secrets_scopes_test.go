@@ -0,0 +1,125 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/outblocks/outblocks-cli/pkg/lockfile"
+)
+
+func TestSecretsScopes(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	if err := os.Mkdir(filepath.Join(dir, "app"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	data := []byte(`name: test
+secrets:
+  type: file
+  scopes:
+    service.payments: [STRIPE_*]
+    hook: [STRIPE_WEBHOOK_SECRET]
+`)
+
+	vals := map[string]interface{}{
+		"secrets": map[string]interface{}{
+			"STRIPE_KEY":            "sk",
+			"STRIPE_WEBHOOK_SECRET": "wh",
+			"DB_PASSWORD":           "db",
+		},
+	}
+
+	load := func(t *testing.T, app string) error {
+		t.Helper()
+
+		p, err := LoadProjectConfigData(filepath.Join(dir, "project.outblocks.yaml"), data, vals, nil, &ProjectOptions{Env: "dev"}, &lockfile.Lockfile{})
+		if err != nil {
+			t.Fatalf("unexpected load error: %v", err)
+		}
+
+		file := filepath.Join(dir, "app", "app.outblocks.yaml")
+
+		if err := os.WriteFile(file, []byte(app), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := p.LoadAppFile(file, nil); err != nil {
+			t.Fatalf("unexpected app load error: %v", err)
+		}
+
+		return p.Normalize()
+	}
+
+	err := load(t, `name: payments
+type: service
+env:
+  KEY: ${secrets.STRIPE_KEY}
+  DB: ${secrets.DB_PASSWORD}
+`)
+	if err != nil {
+		t.Fatalf("unexpected error for allowed secrets: %v", err)
+	}
+
+	err = load(t, `name: api
+type: service
+env:
+  DB: ${secrets.DB_PASSWORD}
+  KEY: ${secrets.STRIPE_KEY}
+`)
+	if err == nil || !strings.Contains(err.Error(), "secret 'STRIPE_KEY' is not in scope") || !strings.Contains(err.Error(), "app/app.outblocks.yaml:5:") {
+		t.Fatalf("expected scope violation error with location, got: %v", err)
+	}
+
+	p, err := LoadProjectConfigData(filepath.Join(dir, "project.outblocks.yaml"), data, vals, nil, &ProjectOptions{Env: "dev"}, &lockfile.Lockfile{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.Normalize(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		app, key string
+		allowed  bool
+	}{
+		{"app_service_payments", "STRIPE_KEY", true},
+		{"app_service_api", "STRIPE_KEY", false},
+		{"app_function_hook", "STRIPE_WEBHOOK_SECRET", true},
+		{"app_function_hook", "STRIPE_KEY", false},
+		{"app_service_api", "DB_PASSWORD", true},
+		{"", "DB_PASSWORD", true},
+		{"", "STRIPE_KEY", false},
+	}
+
+	for _, tt := range tests {
+		if got := p.Secrets.IsAllowed(tt.app, tt.key); got != tt.allowed {
+			t.Errorf("IsAllowed(%q, %q) = %v, want %v", tt.app, tt.key, got, tt.allowed)
+		}
+	}
+
+	bad := []byte(`name: test
+secrets:
+  type: file
+  scopes:
+    service.payments: [STRIPE_*]
+dependencies:
+  db:
+    type: postgresql
+    password: ${secrets.STRIPE_KEY}
+`)
+
+	p, err = LoadProjectConfigData(filepath.Join(dir, "project.outblocks.yaml"), bad, vals, nil, &ProjectOptions{Env: "dev"}, &lockfile.Lockfile{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.Normalize(); err == nil || !strings.Contains(err.Error(), "cannot be referenced in project config") {
+		t.Fatalf("expected error for scoped secret in project config, got: %v", err)
+	}
+}
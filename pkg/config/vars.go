@@ -105,12 +105,19 @@ func expandYAMLString(n *ast.StringNode, file, env string, vals map[string]inter
 }
 
 func traverseYAMLMapping(node ast.Node, file, env string, vals map[string]interface{}, essentialKeys map[string]bool, path []string) (ast.Node, error) {
+	return walkYAMLMapping(node, path, func(n *ast.StringNode, path []string) (ast.Node, error) {
+		return expandYAMLString(n, file, env, vals, essentialKeys, path)
+	})
+}
+
+// walkYAMLMapping calls fn for every string node, replacing it with returned node.
+func walkYAMLMapping(node ast.Node, path []string, fn func(n *ast.StringNode, path []string) (ast.Node, error)) (ast.Node, error) {
 	switch n := node.(type) {
 	case *ast.StringNode:
-		return expandYAMLString(n, file, env, vals, essentialKeys, path)
+		return fn(n, path)
 
 	case *ast.MappingValueNode:
-		newVal, err := traverseYAMLMapping(n.Value, file, env, vals, essentialKeys, append(append([]string(nil), path...), n.Key.String()))
+		newVal, err := walkYAMLMapping(n.Value, append(append([]string(nil), path...), n.Key.String()), fn)
 		if err != nil {
 			return nil, err
 		}
@@ -121,7 +128,7 @@ func traverseYAMLMapping(node ast.Node, file, env string, vals map[string]interf
 		for _, v := range n.Values {
 			keyPath := append(append([]string(nil), path...), v.Key.String())
 
-			newVal, err := traverseYAMLMapping(v.Value, file, env, vals, essentialKeys, keyPath)
+			newVal, err := walkYAMLMapping(v.Value, keyPath, fn)
 			if err != nil {
 				return nil, err
 			}
@@ -131,7 +138,7 @@ func traverseYAMLMapping(node ast.Node, file, env string, vals map[string]interf
 
 	case *ast.SequenceNode:
 		for i, v := range n.Values {
-			newVal, err := traverseYAMLMapping(v, file, env, vals, essentialKeys, path)
+			newVal, err := walkYAMLMapping(v, path, fn)
 			if err != nil {
 				return nil, err
 			}
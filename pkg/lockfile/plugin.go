@@ -9,6 +9,8 @@ type Plugin struct {
 	Name    string          `json:"name" valid:"required"`
 	Version *semver.Version `json:"version" valid:"required"`
 	Source  string          `json:"source" valid:"required"`
+	// Checksums of plugin contents keyed by arch, e.g. linux_amd64.
+	Checksums map[string]string `json:"checksums,omitempty"`
 }
 
 func (p *Plugin) Matches(name string, ver *semver.Version, source string) bool {
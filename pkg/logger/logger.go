@@ -2,6 +2,8 @@ package logger
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"runtime"
 	"strings"
 
@@ -31,6 +33,8 @@ type Log struct {
 }
 
 func NewLogger() Logger {
+	setupRedactedOutput()
+
 	debug := pterm.Debug.WithDebugger(false).WithPrefix(pterm.Prefix{
 		Style: &pterm.ThemeDefault.DebugPrefixStyle,
 		Text:  "DEBG",
@@ -84,13 +88,16 @@ func NewLogger() Logger {
 	return l
 }
 
+// plainOutput is used instead of pterm when not running in terminal, it is redacted the same way as pterm output.
+var plainOutput io.Writer = os.Stdout
+
 func printer(a ...interface{}) {
 	if !util.IsTerminal() {
 		if !pterm.Output {
 			return
 		}
 
-		color.Print(a...)
+		color.Fprint(RedactWriter(plainOutput), a...)
 
 		return
 	}
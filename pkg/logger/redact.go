@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"encoding/base64"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pterm/pterm"
+)
+
+const (
+	RedactedValue = "***"
+
+	// Values shorter than that are not redacted as they would match too often.
+	redactMinLength = 4
+)
+
+// Redactor replaces known secret values, including their encoded forms, with a placeholder.
+type Redactor struct {
+	mu       sync.RWMutex
+	values   map[string]struct{}
+	replacer *strings.Replacer
+}
+
+var (
+	defaultRedactor = NewRedactor()
+	redactOutput    sync.Once
+)
+
+func NewRedactor() *Redactor {
+	return &Redactor{
+		values: make(map[string]struct{}),
+	}
+}
+
+// Add registers secret values to be redacted along with their base64 and URL-encoded forms.
+func (r *Redactor) Add(vals ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, v := range vals {
+		if len(v) < redactMinLength {
+			continue
+		}
+
+		for _, form := range []string{
+			v,
+			base64.StdEncoding.EncodeToString([]byte(v)),
+			base64.RawStdEncoding.EncodeToString([]byte(v)),
+			base64.URLEncoding.EncodeToString([]byte(v)),
+			base64.RawURLEncoding.EncodeToString([]byte(v)),
+			url.QueryEscape(v),
+			url.PathEscape(v),
+		} {
+			r.values[form] = struct{}{}
+		}
+	}
+
+	// Longest values first so that value containing another one is redacted as a whole.
+	sorted := make([]string, 0, len(r.values))
+
+	for v := range r.values {
+		sorted = append(sorted, v)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		if len(sorted[i]) != len(sorted[j]) {
+			return len(sorted[i]) > len(sorted[j])
+		}
+
+		return sorted[i] < sorted[j]
+	})
+
+	oldnew := make([]string, 0, len(sorted)*2)
+
+	for _, v := range sorted {
+		oldnew = append(oldnew, v, RedactedValue)
+	}
+
+	r.replacer = strings.NewReplacer(oldnew...)
+}
+
+func (r *Redactor) Redact(s string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.replacer == nil {
+		return s
+	}
+
+	return r.replacer.Replace(s)
+}
+
+// Writer returns writer redacting everything written through it.
+func (r *Redactor) Writer(w io.Writer) io.Writer {
+	return &redactWriter{r: r, w: w}
+}
+
+type redactWriter struct {
+	r *Redactor
+	w io.Writer
+}
+
+func (w *redactWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(w.w, w.r.Redact(string(p))); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// AddRedactedValues registers secret values to be redacted from all log output.
+func AddRedactedValues(vals ...string) {
+	defaultRedactor.Add(vals...)
+}
+
+// Redact replaces registered secret values in s.
+func Redact(s string) string {
+	return defaultRedactor.Redact(s)
+}
+
+// RedactWriter wraps w so that registered secret values are redacted from everything written to it.
+func RedactWriter(w io.Writer) io.Writer {
+	return defaultRedactor.Writer(w)
+}
+
+// setupRedactedOutput routes all pterm output through redactor.
+func setupRedactedOutput() {
+	redactOutput.Do(func() {
+		pterm.SetDefaultOutput(RedactWriter(os.Stdout))
+	})
+}
@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/url"
+	"testing"
+
+	"github.com/outblocks/outblocks-cli/internal/util"
+)
+
+func TestRedactor(t *testing.T) {
+	r := NewRedactor()
+
+	if got := r.Redact("nothing to hide"); got != "nothing to hide" {
+		t.Fatalf("unexpected redaction without values: %s", got)
+	}
+
+	secret := "p@ss word/1"
+	r.Add(secret, "abc")
+
+	tests := map[string]string{
+		"password is " + secret: "password is ***",
+		"b64 " + base64.StdEncoding.EncodeToString([]byte(secret)):       "b64 ***",
+		"b64url " + base64.RawURLEncoding.EncodeToString([]byte(secret)): "b64url ***",
+		"query ?p=" + url.QueryEscape(secret):                            "query ?p=***",
+		"path /" + url.PathEscape(secret):                                "path /***",
+		"short abc values are kept":                                      "short abc values are kept",
+	}
+
+	for in, want := range tests {
+		if got := r.Redact(in); got != want {
+			t.Errorf("Redact(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	var buf bytes.Buffer
+
+	w := r.Writer(&buf)
+
+	n, err := w.Write([]byte("secret: " + secret + "\n"))
+	if err != nil || n != len("secret: "+secret+"\n") {
+		t.Fatalf("unexpected write result: %d, %v", n, err)
+	}
+
+	if buf.String() != "secret: ***\n" {
+		t.Fatalf("unexpected writer output: %q", buf.String())
+	}
+}
+
+func TestLoggerRedactsPlainOutput(t *testing.T) {
+	if util.IsTerminal() {
+		t.Skip("plain output is used only when not running in terminal")
+	}
+
+	var buf bytes.Buffer
+
+	old := plainOutput
+	plainOutput = &buf
+
+	defer func() { plainOutput = old }()
+
+	AddRedactedValues("plain-output-secret")
+
+	l := NewLogger()
+	l.Printf("token: %s\n", "plain-output-secret")
+	l.Println("again", "plain-output-secret")
+	l.Printo("over", "plain-output-secret")
+
+	want := "token: ***\nagain ***\nover ***\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected plain output: %q, want %q", buf.String(), want)
+	}
+}
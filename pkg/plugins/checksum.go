@@ -0,0 +1,107 @@
+package plugins
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ansel1/merry/v2"
+)
+
+const checksumPrefix = "sha256:"
+
+// Skipped as VCS sourced plugins are used with their repository metadata.
+var checksumSkipDirs = map[string]bool{
+	".git": true,
+	".hg":  true,
+	".svn": true,
+	".bzr": true,
+}
+
+// DirChecksum computes SHA-256 of plugin dir contents. It hashes a sorted list of file paths along with hashes of their contents
+// so that it only depends on extracted contents and not on archive format or file metadata.
+func DirChecksum(dir string) (string, error) {
+	var lines []string
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if path != dir && checksumSkipDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		h := sha256.New()
+
+		if d.Type()&fs.ModeSymlink != 0 {
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+
+			_, _ = h.Write([]byte(target))
+		} else {
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+
+			_, err = io.Copy(h, f)
+			_ = f.Close()
+
+			if err != nil {
+				return err
+			}
+		}
+
+		lines = append(lines, fmt.Sprintf("%x  %s\n", h.Sum(nil), filepath.ToSlash(rel)))
+
+		return nil
+	})
+	if err != nil {
+		return "", merry.Errorf("cannot compute checksum of %s: %w", dir, err)
+	}
+
+	sort.Strings(lines)
+
+	h := sha256.New()
+
+	for _, l := range lines {
+		_, _ = h.Write([]byte(l))
+	}
+
+	return checksumPrefix + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// PlatformArch converts platform in a form of <os>/<arch>, e.g. linux/amd64, to plugin arch as used in plugin release assets.
+func PlatformArch(platform string) (string, error) {
+	parts := strings.FieldsFunc(platform, func(r rune) bool {
+		return r == '/' || r == '_'
+	})
+
+	if len(parts) != 2 {
+		return "", merry.Errorf("invalid platform '%s', specify in a form of <os>/<arch>, e.g. linux/amd64", platform)
+	}
+
+	if parts[1] == "arm" {
+		parts[1] = "armv6"
+	}
+
+	return parts[0] + "_" + parts[1], nil
+}
@@ -0,0 +1,126 @@
+package plugins
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/outblocks/outblocks-cli/pkg/lockfile"
+)
+
+func writePluginDir(t *testing.T, dir, bin string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Join(dir, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"plugin.yaml": "name: test\ncmd:\n  default: ./plugin\nactions: [deploy]\n",
+		"plugin":      bin,
+		".git/HEAD":   bin,
+	}
+
+	for f, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, f), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDirChecksum(t *testing.T) {
+	t.Parallel()
+
+	a, b := t.TempDir(), t.TempDir()
+
+	writePluginDir(t, a, "v1")
+	writePluginDir(t, b, "v1")
+
+	if err := os.WriteFile(filepath.Join(b, ".git", "HEAD"), []byte("other"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sumA, err := DirChecksum(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sumB, err := DirChecksum(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(sumA, "sha256:") || sumA != sumB {
+		t.Fatalf("expected equal checksums ignoring VCS metadata, got %s and %s", sumA, sumB)
+	}
+
+	writePluginDir(t, b, "v2")
+
+	sumB, err = DirChecksum(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sumA == sumB {
+		t.Fatal("expected checksum to change with contents")
+	}
+}
+
+func TestPlatformArch(t *testing.T) {
+	t.Parallel()
+
+	for in, want := range map[string]string{
+		"linux/amd64":  "linux_amd64",
+		"darwin/arm64": "darwin_arm64",
+		"linux/arm":    "linux_armv6",
+		"linux_amd64":  "linux_amd64",
+	} {
+		got, err := PlatformArch(in)
+		if err != nil || got != want {
+			t.Errorf("PlatformArch(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+
+	if _, err := PlatformArch("linux"); err == nil {
+		t.Error("expected error for invalid platform")
+	}
+}
+
+func TestLoadPluginVerifiesChecksum(t *testing.T) {
+	t.Parallel()
+
+	base := t.TempDir()
+	ver := semver.MustParse("1.0.0")
+	src := "https://github.com/outblocks/cli-plugin-test"
+
+	writePluginDir(t, filepath.Join(base, ".outblocks", "plugins", "outblocks", "test", CurrentArch()+"-1.0.0"), "v1")
+
+	l := NewLoader(base, t.TempDir())
+
+	plug, err := l.LoadPlugin(context.Background(), "test", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	locked := plug.Locked()
+	if locked.Checksums[CurrentArch()] == "" {
+		t.Fatal("expected checksum for current arch to be recorded")
+	}
+
+	lock := &lockfile.Plugin{Name: "test", Version: ver, Source: src, Checksums: locked.Checksums}
+
+	if _, err := l.LoadPlugin(context.Background(), "test", "", nil, lock); err != nil {
+		t.Fatalf("unexpected error for matching checksum: %v", err)
+	}
+
+	lock.Checksums = map[string]string{CurrentArch(): "sha256:invalid", "other_arch": "sha256:other"}
+
+	_, err = l.LoadPlugin(context.Background(), "test", "", nil, lock)
+	if !errors.Is(err, ErrPluginChecksumMismatch) {
+		t.Fatalf("expected checksum mismatch error, got: %v", err)
+	}
+}
@@ -12,7 +12,6 @@ import (
 
 	"github.com/ansel1/merry/v2"
 	"github.com/outblocks/outblocks-cli/pkg/logger"
-	plugin_go "github.com/outblocks/outblocks-plugin-go"
 	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -21,9 +20,10 @@ import (
 type Client struct {
 	log logger.Logger
 
-	cmd      *exec.Cmd
-	addr     string
-	hostAddr string
+	cmd       *exec.Cmd
+	addr      string
+	host      Host
+	hostToken bool
 
 	conn *grpc.ClientConn
 
@@ -31,11 +31,14 @@ type Client struct {
 	env         string
 	props       map[string]interface{}
 	yamlContext YAMLContext
+	timeouts    Timeouts
 
 	once struct {
 		init, start sync.Once
 	}
 	mu sync.Mutex
+
+	stderr *stderrCapture
 }
 
 type YAMLContext struct {
@@ -47,19 +50,33 @@ const (
 	DefaultTimeout = 60 * time.Second
 )
 
-func NewClient(log logger.Logger, name, env string, cmd *exec.Cmd, hostAddr string, props map[string]interface{}, yamlContext YAMLContext) (*Client, error) {
+// Host provides endpoints of host gRPC server to plugins.
+type Host interface {
+	// Endpoint returns address of host server dedicated to plugin, requiring host token on calls if plugin supports sending it.
+	Endpoint(plugin string, requireToken bool) (string, error)
+	// BeginScope makes secrets scoped to apps available to plugin until returned function is called.
+	BeginScope(plugin string, appIDs []string) (end func())
+}
+
+func NewClient(log logger.Logger, name, env string, cmd *exec.Cmd, host Host, props map[string]interface{}, yamlContext YAMLContext) (*Client, error) {
 	return &Client{
-		log:      log,
-		cmd:      cmd,
-		hostAddr: hostAddr,
+		log:  log,
+		cmd:  cmd,
+		host: host,
 
 		name:        name,
 		env:         env,
 		props:       props,
 		yamlContext: yamlContext,
+		timeouts:    DefaultTimeouts,
 	}, nil
 }
 
+// SetTimeouts sets deadlines of plugin RPCs, it has to be called before plugin is started.
+func (c *Client) SetTimeouts(t Timeouts) {
+	c.timeouts = t
+}
+
 func (c *Client) basicPlugin() apiv1.BasicPluginServiceClient {
 	return apiv1.NewBasicPluginServiceClient(c.conn)
 }
@@ -136,11 +153,17 @@ func (c *Client) init(ctx context.Context) error {
 		for s.Scan() {
 			b := s.Bytes()
 
+			if c.stderr != nil {
+				c.stderr.add(string(b))
+
+				continue
+			}
+
 			c.log.Errorf("%s%s\n", prefix, string(b))
 		}
 	}()
 
-	var handshake *plugin_go.Handshake
+	var handshake *pluginHandshake
 
 	if err := json.Unmarshal(line, &handshake); err != nil {
 		return c.newPluginError("handshake error", merry.Wrap(err))
@@ -150,13 +173,18 @@ func (c *Client) init(ctx context.Context) error {
 		return c.newPluginError("handshake not returned by plugin", merry.Wrap(err))
 	}
 
-	if err := ValidateHandshake(handshake); err != nil {
+	if err := ValidateHandshake(&handshake.Handshake); err != nil {
 		return c.newPluginError("invalid handshake", merry.Wrap(err))
 	}
 
 	c.addr = handshake.Addr
+	c.hostToken = handshake.HostToken
 
-	c.conn, err = grpc.NewClient(c.addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	c.conn, err = grpc.NewClient(c.addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(c.unaryInterceptor),
+		grpc.WithChainStreamInterceptor(c.streamInterceptor),
+	)
 	if err != nil {
 		return err
 	}
@@ -185,3 +213,12 @@ func (c *Client) Stop() error {
 
 	return cmd.Wait()
 }
+
+// appScope makes secrets scoped to apps available to plugin for duration of a call operating on them.
+func (c *Client) appScope(appIDs []string) (end func()) {
+	if len(appIDs) == 0 {
+		return func() {}
+	}
+
+	return c.host.BeginScope(c.name, appIDs)
+}
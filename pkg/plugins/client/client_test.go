@@ -0,0 +1,165 @@
+package client
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/outblocks/outblocks-cli/internal/statefile"
+	"github.com/outblocks/outblocks-cli/pkg/logger"
+	"github.com/outblocks/outblocks-cli/pkg/server"
+	plugin_go "github.com/outblocks/outblocks-plugin-go"
+	"github.com/outblocks/outblocks-plugin-go/env"
+	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
+	"github.com/outblocks/outblocks-plugin-go/log"
+	"github.com/outblocks/outblocks-plugin-go/registry"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const helperPluginEnv = "OUTBLOCKS_TEST_HELPER_PLUGIN"
+
+// helperPlugin is a plugin built with plugin SDK that calls host server the way real plugins do.
+type helperPlugin struct {
+	host apiv1.HostServiceClient
+}
+
+func (p *helperPlugin) Init(ctx context.Context, _ env.Enver, _ log.Logger, host apiv1.HostServiceClient) error {
+	p.host = host
+
+	_, err := host.Log(ctx, &apiv1.LogRequest{Message: "initialized", Level: apiv1.LogRequest_LEVEL_DEBUG})
+
+	return err
+}
+
+func (p *helperPlugin) Start(context.Context, *apiv1.StartRequest) (*apiv1.StartResponse, error) {
+	return &apiv1.StartResponse{}, nil
+}
+
+func (p *helperPlugin) ProjectInit(context.Context, *apiv1.ProjectInitRequest) (*apiv1.ProjectInitResponse, error) {
+	return &apiv1.ProjectInitResponse{}, nil
+}
+
+// Plan requests secret passed in args from host server and fails if it is not available.
+func (p *helperPlugin) Plan(ctx context.Context, _ *registry.Registry, req *apiv1.PlanRequest) (*apiv1.PlanResponse, error) {
+	res, err := p.host.HostGetSecret(ctx, &apiv1.HostGetSecretRequest{Key: req.Args.Fields["secret"].GetStringValue()})
+	if err != nil {
+		return nil, err
+	}
+
+	if !res.Specified {
+		return nil, status.Error(codes.NotFound, "secret not available")
+	}
+
+	return &apiv1.PlanResponse{}, nil
+}
+
+func (p *helperPlugin) Apply(*apiv1.ApplyRequest, *registry.Registry, apiv1.DeployPluginService_ApplyServer) error {
+	return nil
+}
+
+// TestHelperPlugin is not a real test, it runs helper plugin when started by startHelperPlugin.
+func TestHelperPlugin(t *testing.T) {
+	if os.Getenv(helperPluginEnv) != "1" {
+		t.Skip("helper plugin process")
+	}
+
+	if err := plugin_go.Serve(&helperPlugin{}); err != nil {
+		os.Exit(1)
+	}
+
+	os.Exit(0)
+}
+
+func startHelperPlugin(t *testing.T, srv *server.Server) *Client {
+	t.Helper()
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestHelperPlugin$")
+	cmd.Env = append(os.Environ(), helperPluginEnv+"=1")
+
+	c, err := NewClient(logger.NewLogger(), "helper", "dev", cmd, srv, nil, YAMLContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = c.Stop() })
+
+	return c
+}
+
+func TestClientWithSDKPlugin(t *testing.T) {
+	t.Parallel()
+
+	srv := server.NewServer(logger.NewLogger(), map[string]interface{}{})
+
+	if err := srv.Serve(); err != nil {
+		t.Fatal(err)
+	}
+
+	defer srv.Stop()
+
+	c := startHelperPlugin(t, srv)
+
+	// Plugin SDK does not send host token, its calls to host server have to succeed without it.
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatalf("unexpected error starting plugin: %v", err)
+	}
+
+	if c.hostToken {
+		t.Fatal("plugin SDK does not declare host token support")
+	}
+}
+
+func TestClientSecretsScope(t *testing.T) {
+	t.Parallel()
+
+	srv := server.NewServer(logger.NewLogger(), map[string]interface{}{"STRIPE_KEY": "sk", "DB_URL": "db"})
+
+	if err := srv.Serve(); err != nil {
+		t.Fatal(err)
+	}
+
+	defer srv.Stop()
+
+	srv.SetSecretsFilter(func(appID, key string) bool {
+		return key != "STRIPE_KEY" || appID == "app_service_payments"
+	})
+
+	c := startHelperPlugin(t, srv)
+
+	appPlans := func(ids ...string) []*apiv1.AppPlan {
+		var apps []*apiv1.AppPlan
+
+		for _, id := range ids {
+			apps = append(apps, &apiv1.AppPlan{State: &apiv1.AppState{App: &apiv1.App{Id: id}}})
+		}
+
+		return apps
+	}
+
+	tests := []struct {
+		name    string
+		apps    []*apiv1.AppPlan
+		secret  string
+		allowed bool
+	}{
+		{"scoped secret for app in scope", appPlans("app_service_payments"), "STRIPE_KEY", true},
+		{"scoped secret for app in scope among others", appPlans("app_static_web", "app_service_payments"), "STRIPE_KEY", true},
+		{"scoped secret for other app", appPlans("app_static_web"), "STRIPE_KEY", false},
+		{"scoped secret without apps", nil, "STRIPE_KEY", false},
+		{"unscoped secret", appPlans("app_static_web"), "DB_URL", true},
+	}
+
+	for _, tt := range tests {
+		_, err := c.Plan(context.Background(), &statefile.StateData{}, tt.apps, nil, 0, map[string]interface{}{"secret": tt.secret}, false, false)
+		if tt.allowed && err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+		}
+
+		if !tt.allowed && (err == nil || !strings.Contains(err.Error(), "secret not available")) {
+			t.Errorf("%s: expected secret not to be available, got: %v", tt.name, err)
+		}
+	}
+}
@@ -14,6 +14,8 @@ func (c *Client) Plan(ctx context.Context, state *statefile.StateData, apps []*a
 		return nil, err
 	}
 
+	defer c.appScope(appPlanIDs(apps))()
+
 	pluginState := state.Plugins[c.name]
 	if pluginState == nil {
 		pluginState = &statefile.PluginState{}
@@ -39,6 +41,8 @@ func (c *Client) Apply(ctx context.Context, state *statefile.StateData, apps []*
 		return nil, err
 	}
 
+	defer c.appScope(appPlanIDs(apps))()
+
 	stream, err := c.deployPlugin().Apply(ctx, &apiv1.ApplyRequest{
 		Apps:         apps,
 		Dependencies: deps,
@@ -81,3 +85,13 @@ func (c *Client) Apply(ctx context.Context, state *statefile.StateData, apps []*
 		return nil, c.newPluginError("unexpected response to apply request", merry.Wrap(err))
 	}
 }
+
+func appPlanIDs(apps []*apiv1.AppPlan) []string {
+	ids := make([]string, len(apps))
+
+	for i, a := range apps {
+		ids[i] = a.GetState().GetApp().GetId()
+	}
+
+	return ids
+}
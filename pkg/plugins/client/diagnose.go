@@ -0,0 +1,125 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ansel1/merry/v2"
+	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Services are all gRPC services that plugins may implement.
+var Services = []string{
+	apiv1.BasicPluginService_ServiceDesc.ServiceName,
+	apiv1.StatePluginService_ServiceDesc.ServiceName,
+	apiv1.LockingPluginService_ServiceDesc.ServiceName,
+	apiv1.DeployPluginService_ServiceDesc.ServiceName,
+	apiv1.LogsPluginService_ServiceDesc.ServiceName,
+	apiv1.DNSPluginService_ServiceDesc.ServiceName,
+	apiv1.RunPluginService_ServiceDesc.ServiceName,
+	apiv1.CommandPluginService_ServiceDesc.ServiceName,
+	apiv1.DeployHookService_ServiceDesc.ServiceName,
+	apiv1.SecretPluginService_ServiceDesc.ServiceName,
+	apiv1.MonitoringPluginService_ServiceDesc.ServiceName,
+}
+
+// stderrGracePeriod is how long to wait for plugin stderr to be read after startup.
+const stderrGracePeriod = 200 * time.Millisecond
+
+// servicePingMethod is a method that no service implements, calling it tells if service is registered without side effects.
+const servicePingMethod = "OutblocksPing"
+
+type stderrCapture struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (s *stderrCapture) add(line string) {
+	s.mu.Lock()
+	s.lines = append(s.lines, line)
+	s.mu.Unlock()
+}
+
+func (s *stderrCapture) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return strings.Join(s.lines, "\n")
+}
+
+// Diagnosis is a result of starting plugin and checking its gRPC services.
+type Diagnosis struct {
+	// Handshake is an error of starting plugin process or validating its handshake.
+	Handshake error
+	// Init is an error of plugin Init call.
+	Init error
+	// Services maps service name to true if plugin registered it, unreachable services are skipped.
+	Services map[string]bool
+	// Ping is an error of pinging plugin services.
+	Ping   error
+	Stderr string
+}
+
+// Diagnose starts plugin capturing its stderr, validates handshake and pings all known gRPC services.
+// It has to be called before plugin is started in any other way.
+func (c *Client) Diagnose(ctx context.Context) *Diagnosis {
+	d := &Diagnosis{
+		Services: make(map[string]bool),
+	}
+
+	c.stderr = &stderrCapture{}
+
+	c.once.init.Do(func() {
+		if err := c.init(ctx); err != nil {
+			d.Handshake = err
+
+			return
+		}
+
+		if err := c.initPlugin(ctx); err != nil {
+			d.Init = c.mapError("init error", merry.Wrap(err))
+		}
+	})
+
+	if d.Handshake == nil && c.conn != nil {
+		for _, svc := range Services {
+			ok, err := c.pingService(ctx, svc)
+			if err != nil {
+				d.Ping = c.mapError("ping error", merry.Wrap(err))
+
+				break
+			}
+
+			d.Services[svc] = ok
+		}
+	}
+
+	time.Sleep(stderrGracePeriod)
+
+	d.Stderr = c.stderr.String()
+
+	return d
+}
+
+func (c *Client) pingService(ctx context.Context, svc string) (bool, error) {
+	err := c.conn.Invoke(ctx, "/"+svc+"/"+servicePingMethod, &emptypb.Empty{}, &emptypb.Empty{})
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return false, err
+	}
+
+	switch {
+	case st.Code() == codes.Unimplemented && strings.HasPrefix(st.Message(), "unknown service"):
+		return false, nil
+	case st.Code() == codes.Unimplemented, st.Code() == codes.OK:
+		return true, nil
+	}
+
+	return false, err
+}
@@ -7,6 +7,16 @@ import (
 	plugin_go "github.com/outblocks/outblocks-plugin-go"
 )
 
+// pluginHandshake is a handshake printed by plugin on startup.
+// HostToken is set by plugins that send host token with every call to host server, host server requires it from them.
+// Plugins without it are served only on unix sockets with a deprecation warning, host token is planned to become
+// mandatory for all plugins once official plugins send it.
+type pluginHandshake struct {
+	plugin_go.Handshake
+
+	HostToken bool `json:"host_token"`
+}
+
 func ValidateHandshake(h *plugin_go.Handshake) error {
 	return validation.ValidateStruct(h,
 		validation.Field(&h.Protocol, validation.Required, validation.Match(regexp.MustCompile(`^(v1)$`))),
@@ -14,6 +14,8 @@ func (c *Client) DeployHook(ctx context.Context, stage apiv1.DeployHookRequest_S
 		return nil, err
 	}
 
+	defer c.appScope(appPlanIDs(apps))()
+
 	pluginState := state.Plugins[c.name]
 	if pluginState == nil {
 		pluginState = &statefile.PluginState{}
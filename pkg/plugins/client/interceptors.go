@@ -0,0 +1,305 @@
+package client
+
+import (
+	"context"
+	"io"
+	"time"
+
+	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	rpcRetryMaxAttempts = 3
+	rpcRetryDelay       = 500 * time.Millisecond
+)
+
+// Timeouts are deadlines of plugin RPCs per family, zero means no deadline.
+type Timeouts struct {
+	Plan  time.Duration
+	Apply time.Duration
+	State time.Duration
+	Lock  time.Duration
+	Logs  time.Duration
+}
+
+// DefaultTimeouts are used for RPC families without configured timeout.
+// Plan, apply and logs are unbounded by default as they may run for as long as cloud operations take.
+var DefaultTimeouts = Timeouts{
+	State: DefaultTimeout,
+	Lock:  DefaultTimeout,
+}
+
+func fullMethod(svc, method string) string {
+	return "/" + svc + "/" + method
+}
+
+var (
+	deployService     = apiv1.DeployPluginService_ServiceDesc.ServiceName
+	dnsService        = apiv1.DNSPluginService_ServiceDesc.ServiceName
+	monitoringService = apiv1.MonitoringPluginService_ServiceDesc.ServiceName
+	stateService      = apiv1.StatePluginService_ServiceDesc.ServiceName
+	lockingService    = apiv1.LockingPluginService_ServiceDesc.ServiceName
+	logsService       = apiv1.LogsPluginService_ServiceDesc.ServiceName
+	secretService     = apiv1.SecretPluginService_ServiceDesc.ServiceName
+
+	rpcTimeouts = map[string]func(*Timeouts) time.Duration{
+		fullMethod(deployService, "Plan"):               func(t *Timeouts) time.Duration { return t.Plan },
+		fullMethod(dnsService, "PlanDNS"):               func(t *Timeouts) time.Duration { return t.Plan },
+		fullMethod(monitoringService, "PlanMonitoring"): func(t *Timeouts) time.Duration { return t.Plan },
+
+		fullMethod(deployService, "Apply"):                                        func(t *Timeouts) time.Duration { return t.Apply },
+		fullMethod(dnsService, "ApplyDNS"):                                        func(t *Timeouts) time.Duration { return t.Apply },
+		fullMethod(monitoringService, "ApplyMonitoring"):                          func(t *Timeouts) time.Duration { return t.Apply },
+		fullMethod(apiv1.DeployHookService_ServiceDesc.ServiceName, "DeployHook"): func(t *Timeouts) time.Duration { return t.Apply },
+		fullMethod(stateService, "GetState"):                                      func(t *Timeouts) time.Duration { return t.State },
+		fullMethod(stateService, "SaveState"):                                     func(t *Timeouts) time.Duration { return t.State },
+		fullMethod(stateService, "ReleaseStateLock"):                              func(t *Timeouts) time.Duration { return t.State },
+		fullMethod(lockingService, "AcquireLocks"):                                func(t *Timeouts) time.Duration { return t.Lock },
+		fullMethod(lockingService, "ReleaseLocks"):                                func(t *Timeouts) time.Duration { return t.Lock },
+		fullMethod(logsService, "Logs"):                                           func(t *Timeouts) time.Duration { return t.Logs },
+	}
+
+	// retryableRPCs are idempotent RPCs that are retried with backoff when plugin is unavailable.
+	retryableRPCs = map[string]bool{
+		fullMethod(stateService, "GetState"):       true,
+		fullMethod(lockingService, "AcquireLocks"): true,
+		fullMethod(secretService, "GetSecret"):     true,
+		fullMethod(secretService, "GetSecrets"):    true,
+	}
+)
+
+// timeoutExtension is a call option extending RPC timeout, e.g. by time spent waiting for a lock.
+type timeoutExtension struct {
+	grpc.EmptyCallOption
+
+	d time.Duration
+}
+
+func withTimeoutExtension(d time.Duration) grpc.CallOption {
+	return timeoutExtension{d: d}
+}
+
+func (c *Client) timeoutFor(method string, opts []grpc.CallOption) time.Duration {
+	f, ok := rpcTimeouts[method]
+	if !ok {
+		return 0
+	}
+
+	timeout := f(&c.timeouts)
+	if timeout <= 0 {
+		return 0
+	}
+
+	for _, o := range opts {
+		if ext, ok := o.(timeoutExtension); ok {
+			timeout += ext.d
+		}
+	}
+
+	return timeout
+}
+
+func withOptionalTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+func shouldRetry(method string, attempt int, err error) bool {
+	return retryableRPCs[method] && attempt < rpcRetryMaxAttempts && status.Code(err) == codes.Unavailable
+}
+
+// retryWait waits with exponential backoff before next attempt, it returns false if context is done first.
+func retryWait(ctx context.Context, attempt int) bool {
+	timer := time.NewTimer(rpcRetryDelay << (attempt - 1))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func (c *Client) unaryInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	timeout := c.timeoutFor(method, opts)
+
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		attemptCtx, cancel := withOptionalTimeout(ctx, timeout)
+
+		err := invoker(attemptCtx, method, req, reply, cc, opts...)
+
+		cancel()
+		currentTracer().record(c.name, method, attempt, start, err)
+
+		if !shouldRetry(method, attempt, err) {
+			return err
+		}
+
+		c.log.Debugf("Plugin '%s' unavailable calling %s, retrying: %s\n", c.name, method, err)
+
+		if !retryWait(ctx, attempt) {
+			return err
+		}
+	}
+}
+
+func (c *Client) streamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	s := &clientStream{
+		c:        c,
+		ctx:      ctx,
+		desc:     desc,
+		cc:       cc,
+		method:   method,
+		streamer: streamer,
+		opts:     opts,
+		timeout:  c.timeoutFor(method, opts),
+	}
+
+	for {
+		err := s.open()
+		if err == nil {
+			return s, nil
+		}
+
+		if !s.retry(err) {
+			return nil, err
+		}
+	}
+}
+
+// clientStream wraps stream of a single RPC applying timeout, tracing it and retrying it if nothing was received yet.
+type clientStream struct {
+	grpc.ClientStream
+
+	c        *Client
+	ctx      context.Context
+	desc     *grpc.StreamDesc
+	cc       *grpc.ClientConn
+	method   string
+	streamer grpc.Streamer
+	opts     []grpc.CallOption
+	timeout  time.Duration
+
+	cancel   context.CancelFunc
+	attempt  int
+	start    time.Time
+	req      interface{}
+	closed   bool
+	received bool
+	done     bool
+}
+
+func (s *clientStream) open() error {
+	s.attempt++
+	s.start = time.Now()
+
+	ctx, cancel := withOptionalTimeout(s.ctx, s.timeout)
+
+	stream, err := s.streamer(ctx, s.desc, s.cc, s.method, s.opts...)
+	if err != nil {
+		cancel()
+
+		return err
+	}
+
+	s.ClientStream = stream
+	s.cancel = cancel
+
+	if s.req != nil {
+		if err := stream.SendMsg(s.req); err != nil && err != io.EOF {
+			return err
+		}
+	}
+
+	if s.closed {
+		return stream.CloseSend()
+	}
+
+	return nil
+}
+
+// retry records failed attempt and waits before next one, it returns false if RPC should not be retried.
+func (s *clientStream) retry(err error) bool {
+	if s.cancel != nil {
+		s.cancel()
+	}
+
+	currentTracer().record(s.c.name, s.method, s.attempt, s.start, err)
+
+	if s.received || !s.desc.ServerStreams || s.desc.ClientStreams || !shouldRetry(s.method, s.attempt, err) {
+		return false
+	}
+
+	s.c.log.Debugf("Plugin '%s' unavailable calling %s, retrying: %s\n", s.c.name, s.method, err)
+
+	return retryWait(s.ctx, s.attempt)
+}
+
+// SendMsg and CloseSend are recorded to be replayed on retry, retried streams are server streams only,
+// so they are never called concurrently with RecvMsg.
+func (s *clientStream) SendMsg(m interface{}) error {
+	s.req = m
+
+	return s.ClientStream.SendMsg(m)
+}
+
+func (s *clientStream) CloseSend() error {
+	s.closed = true
+
+	return s.ClientStream.CloseSend()
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	for {
+		err := s.ClientStream.RecvMsg(m)
+		if err == nil {
+			s.received = true
+
+			return nil
+		}
+
+		if s.done {
+			return err
+		}
+
+		if err == io.EOF {
+			s.finish(nil)
+
+			return err
+		}
+
+		if !s.retry(err) {
+			s.done = true
+
+			return err
+		}
+
+		for {
+			openErr := s.open()
+			if openErr == nil {
+				break
+			}
+
+			if !s.retry(openErr) {
+				s.done = true
+
+				return openErr
+			}
+		}
+	}
+}
+
+func (s *clientStream) finish(err error) {
+	s.done = true
+	s.cancel()
+
+	currentTracer().record(s.c.name, s.method, s.attempt, s.start, err)
+}
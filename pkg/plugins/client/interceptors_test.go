@@ -0,0 +1,162 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/outblocks/outblocks-cli/pkg/logger"
+	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+type flakyPlugin struct {
+	apiv1.UnimplementedSecretPluginServiceServer
+	apiv1.UnimplementedStatePluginServiceServer
+	apiv1.UnimplementedDeployPluginServiceServer
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+// fail returns Unavailable error on first call of method.
+func (p *flakyPlugin) fail(method string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.calls[method]++
+
+	if p.calls[method] == 1 {
+		return status.Error(codes.Unavailable, "try again")
+	}
+
+	return nil
+}
+
+func (p *flakyPlugin) GetSecret(_ context.Context, req *apiv1.GetSecretRequest) (*apiv1.GetSecretResponse, error) {
+	if err := p.fail("GetSecret"); err != nil {
+		return nil, err
+	}
+
+	return &apiv1.GetSecretResponse{Value: req.Key, Specified: true}, nil
+}
+
+func (p *flakyPlugin) SetSecret(context.Context, *apiv1.SetSecretRequest) (*apiv1.SetSecretResponse, error) {
+	p.fail("SetSecret") //nolint:errcheck
+
+	return nil, status.Error(codes.Unavailable, "not retried")
+}
+
+func (p *flakyPlugin) GetState(req *apiv1.GetStateRequest, srv apiv1.StatePluginService_GetStateServer) error {
+	if err := p.fail("GetState"); err != nil {
+		return err
+	}
+
+	return srv.Send(&apiv1.GetStateResponse{Response: &apiv1.GetStateResponse_State_{State: &apiv1.GetStateResponse_State{StateName: req.StateType}}})
+}
+
+func (p *flakyPlugin) Plan(ctx context.Context, _ *apiv1.PlanRequest) (*apiv1.PlanResponse, error) {
+	<-ctx.Done()
+
+	return nil, ctx.Err()
+}
+
+func TestInterceptors(t *testing.T) { //nolint:gocyclo
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plug := &flakyPlugin{calls: make(map[string]int)}
+	srv := grpc.NewServer()
+	apiv1.RegisterSecretPluginServiceServer(srv, plug)
+	apiv1.RegisterStatePluginServiceServer(srv, plug)
+	apiv1.RegisterDeployPluginServiceServer(srv, plug)
+
+	go srv.Serve(lis) //nolint:errcheck
+
+	defer srv.Stop()
+
+	var trace bytes.Buffer
+
+	SetTracer(NewTracer(&trace))
+	defer SetTracer(nil)
+
+	c := &Client{log: logger.NewLogger(), name: "test", timeouts: Timeouts{Plan: 50 * time.Millisecond}}
+
+	conn, err := grpc.NewClient(lis.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(c.unaryInterceptor),
+		grpc.WithChainStreamInterceptor(c.streamInterceptor),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close() //nolint:errcheck
+
+	c.conn = conn
+	ctx := context.Background()
+
+	res, err := c.secretPlugin().GetSecret(ctx, &apiv1.GetSecretRequest{Key: "KEY"})
+	if err != nil || res.Value != "KEY" {
+		t.Fatalf("expected idempotent call to be retried, got: %v, %v", res, err)
+	}
+
+	if _, err := c.secretPlugin().SetSecret(ctx, &apiv1.SetSecretRequest{Key: "KEY"}); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected non idempotent call not to be retried, got: %v", err)
+	}
+
+	stream, err := c.statePlugin().GetState(ctx, &apiv1.GetStateRequest{StateType: "gcs"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := stream.Recv()
+	if err != nil || msg.GetState().GetStateName() != "gcs" {
+		t.Fatalf("expected stream to be retried, got: %v, %v", msg, err)
+	}
+
+	if _, err := stream.Recv(); err == nil {
+		t.Fatal("expected stream to end")
+	}
+
+	if _, err := c.deployPlugin().Plan(ctx, &apiv1.PlanRequest{}); status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("expected plan to time out, got: %v", err)
+	}
+
+	plug.mu.Lock()
+	if plug.calls["GetSecret"] != 2 || plug.calls["SetSecret"] != 1 || plug.calls["GetState"] != 2 {
+		t.Errorf("unexpected calls: %v", plug.calls)
+	}
+	plug.mu.Unlock()
+
+	var statuses []string
+
+	for _, line := range strings.Split(strings.TrimSpace(trace.String()), "\n") {
+		var e TraceEntry
+
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatal(err)
+		}
+
+		if e.Plugin != "test" {
+			t.Errorf("unexpected trace entry: %+v", e)
+		}
+
+		statuses = append(statuses, e.RPC[strings.LastIndex(e.RPC, "/")+1:]+":"+e.Status)
+	}
+
+	want := "GetSecret:Unavailable GetSecret:OK SetSecret:Unavailable GetState:Unavailable GetState:OK Plan:DeadlineExceeded"
+	if got := strings.Join(statuses, " "); got != want {
+		t.Errorf("unexpected trace:\n%s\nwant:\n%s", got, want)
+	}
+}
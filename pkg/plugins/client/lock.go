@@ -19,7 +19,7 @@ func (c *Client) AcquireLocks(ctx context.Context, props map[string]interface{},
 		LockNames:  lockNames,
 		LockWait:   durationpb.New(lockWait),
 		Properties: plugin_util.MustNewStruct(props),
-	})
+	}, withTimeoutExtension(lockWait))
 	if err != nil {
 		return nil, c.mapErrorWithContext("acquire locks error", merry.Wrap(err), yamlContext)
 	}
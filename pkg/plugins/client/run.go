@@ -14,14 +14,27 @@ func (c *Client) Run(ctx context.Context, req *apiv1.RunRequest,
 		return nil, err
 	}
 
+	ids := make([]string, len(req.Apps))
+
+	for i, a := range req.Apps {
+		ids[i] = a.GetApp().GetId()
+	}
+
+	// Apps stay in scope for as long as they run.
+	endScope := c.appScope(ids)
+
 	stream, err := c.runPlugin().Run(ctx, req)
 
 	if err != nil {
+		endScope()
+
 		return nil, c.mapError("run error", merry.Wrap(err))
 	}
 
 	res, err := stream.Recv()
 	if err != nil {
+		endScope()
+
 		return nil, c.mapError("run error", merry.Wrap(err))
 	}
 
@@ -29,6 +42,7 @@ func (c *Client) Run(ctx context.Context, req *apiv1.RunRequest,
 		// Seems that everything is running, continue to process messages asynchronously.
 		go func() {
 			defer func() {
+				endScope()
 				close(outCh)
 				close(errCh)
 			}()
@@ -63,5 +77,7 @@ func (c *Client) Run(ctx context.Context, req *apiv1.RunRequest,
 		return r, nil
 	}
 
+	endScope()
+
 	return nil, c.newPluginError("unexpected response to run request", merry.Wrap(err))
 }
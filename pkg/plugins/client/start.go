@@ -17,14 +17,30 @@ func (c *Client) Init(ctx context.Context) error {
 			return
 		}
 
-		_, err = c.basicPlugin().Init(ctx, &apiv1.InitRequest{
-			HostAddr: c.hostAddr,
-		})
+		err = c.initPlugin(ctx)
 	})
 
 	return c.mapError("init error", merry.Wrap(err))
 }
 
+// initPlugin passes address of host server endpoint dedicated to plugin in Init call.
+func (c *Client) initPlugin(ctx context.Context) error {
+	hostAddr, err := c.host.Endpoint(c.name, c.hostToken)
+	if err != nil {
+		return merry.Errorf("cannot create host server endpoint: %w", err)
+	}
+
+	if !c.hostToken {
+		c.log.Warnf("Plugin '%s' does not support host token, its calls to host server are not authenticated. Such plugins are deprecated and will be refused in a future release, update plugin to newer version.\n", c.name)
+	}
+
+	_, err = c.basicPlugin().Init(ctx, &apiv1.InitRequest{
+		HostAddr: hostAddr,
+	})
+
+	return err
+}
+
 func (c *Client) Start(ctx context.Context) error {
 	err := c.Init(ctx)
 	if err != nil {
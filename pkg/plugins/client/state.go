@@ -23,7 +23,7 @@ func (c *Client) GetState(ctx context.Context, typ string, props map[string]inte
 		Lock:       lock,
 		LockWait:   durationpb.New(lockWait),
 		SkipCreate: skipCreate,
-	})
+	}, withTimeoutExtension(lockWait))
 	if err != nil {
 		return nil, c.mapError("get state error", merry.Wrap(err))
 	}
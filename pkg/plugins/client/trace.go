@@ -0,0 +1,78 @@
+package client
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/status"
+)
+
+// TraceEntry is a record of a single plugin RPC attempt.
+type TraceEntry struct {
+	Time       time.Time `json:"time"`
+	Plugin     string    `json:"plugin"`
+	RPC        string    `json:"rpc"`
+	Attempt    int       `json:"attempt"`
+	DurationMS float64   `json:"duration_ms"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Tracer writes trace entries of plugin RPCs as JSON lines.
+type Tracer struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func NewTracer(w io.Writer) *Tracer {
+	return &Tracer{
+		enc: json.NewEncoder(w),
+	}
+}
+
+var (
+	tracer   *Tracer
+	tracerMu sync.RWMutex
+)
+
+// SetTracer sets tracer that RPCs of all plugin clients are recorded with, nil disables tracing.
+func SetTracer(t *Tracer) {
+	tracerMu.Lock()
+	tracer = t
+	tracerMu.Unlock()
+}
+
+func currentTracer() *Tracer {
+	tracerMu.RLock()
+	defer tracerMu.RUnlock()
+
+	return tracer
+}
+
+func (t *Tracer) record(plugin, rpc string, attempt int, start time.Time, err error) {
+	if t == nil {
+		return
+	}
+
+	st := status.Convert(err)
+
+	e := &TraceEntry{
+		Time:       start,
+		Plugin:     plugin,
+		RPC:        rpc,
+		Attempt:    attempt,
+		DurationMS: float64(time.Since(start).Microseconds()) / 1000,
+		Status:     st.Code().String(),
+	}
+
+	if err != nil {
+		e.Error = st.Message()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_ = t.enc.Encode(e)
+}
@@ -0,0 +1,180 @@
+package plugins
+
+import (
+	"bytes"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/ansel1/merry/v2"
+	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
+)
+
+// ActionNames are names of plugin actions in order they are reported in.
+var ActionNames = []string{"deploy", "run", "dns", "lock", "state", "deploy_hook", "secrets", "monitoring"}
+
+var actionServices = map[Action]string{
+	ActionDeploy:     apiv1.DeployPluginService_ServiceDesc.ServiceName,
+	ActionRun:        apiv1.RunPluginService_ServiceDesc.ServiceName,
+	ActionDNS:        apiv1.DNSPluginService_ServiceDesc.ServiceName,
+	ActionLock:       apiv1.LockingPluginService_ServiceDesc.ServiceName,
+	ActionState:      apiv1.StatePluginService_ServiceDesc.ServiceName,
+	ActionDeployHook: apiv1.DeployHookService_ServiceDesc.ServiceName,
+	ActionSecrets:    apiv1.SecretPluginService_ServiceDesc.ServiceName,
+	ActionMonitoring: apiv1.MonitoringPluginService_ServiceDesc.ServiceName,
+}
+
+// ActionService returns name of gRPC service that plugin has to implement for action.
+func ActionService(name string) string {
+	return actionServices[pluginTypes[name]]
+}
+
+var (
+	elfMachines = map[string]elf.Machine{
+		"386":   elf.EM_386,
+		"amd64": elf.EM_X86_64,
+		"arm":   elf.EM_ARM,
+		"arm64": elf.EM_AARCH64,
+	}
+	machoCpus = map[string]macho.Cpu{
+		"386":   macho.Cpu386,
+		"amd64": macho.CpuAmd64,
+		"arm":   macho.CpuArm,
+		"arm64": macho.CpuArm64,
+	}
+	peMachines = map[string]uint16{
+		"386":   pe.IMAGE_FILE_MACHINE_I386,
+		"amd64": pe.IMAGE_FILE_MACHINE_AMD64,
+		"arm":   pe.IMAGE_FILE_MACHINE_ARMNT,
+		"arm64": pe.IMAGE_FILE_MACHINE_ARM64,
+	}
+)
+
+// Executable resolves binary that plugin is run with and checks that it can be executed on current platform.
+// Scripts and binaries of unknown format are only checked to be executable.
+func (p *Plugin) Executable() (string, error) {
+	runCommand := p.runCommand()
+	if runCommand.IsEmpty() {
+		return "", merry.Errorf("no command specified for %s", runtime.GOOS)
+	}
+
+	bin := runCommand.Array()[0]
+
+	if !runCommand.IsArray() {
+		fields := strings.Fields(bin)
+		if len(fields) == 0 {
+			return "", merry.Errorf("no command specified for %s", runtime.GOOS)
+		}
+
+		bin = fields[0]
+	}
+
+	bin = os.Expand(bin, func(key string) string {
+		if key == "OUTBLOCKS_PLUGIN_DIR" {
+			return p.Dir
+		}
+
+		return os.Getenv(key)
+	})
+
+	path, err := exec.LookPath(bin)
+	if err != nil {
+		return bin, merry.Errorf("cannot find plugin executable %s: %w", bin, err)
+	}
+
+	if path, err = filepath.Abs(path); err != nil {
+		return bin, err
+	}
+
+	return path, checkExecutablePlatform(path, runtime.GOOS, runtime.GOARCH)
+}
+
+func checkExecutablePlatform(path, goos, goarch string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close() //nolint:errcheck
+
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(f, magic); err != nil {
+		return nil
+	}
+
+	switch {
+	case bytes.HasPrefix(magic, []byte("#!")):
+		return nil
+
+	case bytes.Equal(magic, []byte(elf.ELFMAG)):
+		ef, err := elf.NewFile(f)
+		if err != nil {
+			return merry.Errorf("invalid ELF executable %s: %w", path, err)
+		}
+
+		if goos == "darwin" || goos == "windows" {
+			return merry.Errorf("%s is a %s ELF executable, not runnable on %s/%s", path, ef.Machine, goos, goarch)
+		}
+
+		if m, ok := elfMachines[goarch]; ok && ef.Machine != m {
+			return merry.Errorf("%s is built for %s, not runnable on %s/%s", path, ef.Machine, goos, goarch)
+		}
+
+	case bytes.HasPrefix(magic, []byte("MZ")):
+		pf, err := pe.NewFile(f)
+		if err != nil {
+			return merry.Errorf("invalid PE executable %s: %w", path, err)
+		}
+
+		if goos != "windows" {
+			return merry.Errorf("%s is a Windows executable, not runnable on %s/%s", path, goos, goarch)
+		}
+
+		if m, ok := peMachines[goarch]; ok && pf.Machine != m {
+			return merry.Errorf("%s is built for machine %#x, not runnable on %s/%s", path, pf.Machine, goos, goarch)
+		}
+
+	default:
+		return checkMachoPlatform(f, path, goos, goarch)
+	}
+
+	return nil
+}
+
+func checkMachoPlatform(f *os.File, path, goos, goarch string) error {
+	var cpus []macho.Cpu
+
+	if ff, err := macho.NewFatFile(f); err == nil {
+		for _, a := range ff.Arches {
+			cpus = append(cpus, a.Cpu)
+		}
+	} else if mf, err := macho.NewFile(f); err == nil {
+		cpus = append(cpus, mf.Cpu)
+	} else {
+		// Unknown format, leave it to the OS.
+		return nil
+	}
+
+	if goos != "darwin" {
+		return merry.Errorf("%s is a macOS executable, not runnable on %s/%s", path, goos, goarch)
+	}
+
+	m, ok := machoCpus[goarch]
+	if !ok {
+		return nil
+	}
+
+	for _, cpu := range cpus {
+		if cpu == m {
+			return nil
+		}
+	}
+
+	return merry.Errorf("%s is built for %s, not runnable on %s/%s", path, cpus, goos, goarch)
+}
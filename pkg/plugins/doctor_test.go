@@ -0,0 +1,70 @@
+package plugins
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/23doors/go-yaml"
+)
+
+func TestPluginExecutable(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	script := filepath.Join(dir, "plugin")
+
+	if err := os.WriteFile(script, []byte("#!/bin/sh\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &Plugin{Dir: dir}
+	if err := yaml.Unmarshal([]byte("cmd:\n  default: $OUTBLOCKS_PLUGIN_DIR/plugin --flag\n"), p); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := p.Executable(); err == nil && runtime.GOOS != "windows" {
+		t.Fatal("expected error for non executable plugin")
+	}
+
+	if err := os.Chmod(script, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := p.Executable()
+	if err != nil || path != script {
+		t.Fatalf("unexpected executable: %s, %v", path, err)
+	}
+}
+
+func TestCheckExecutablePlatform(t *testing.T) {
+	t.Parallel()
+
+	bin, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := checkExecutablePlatform(bin, runtime.GOOS, runtime.GOARCH); err != nil {
+		t.Fatalf("unexpected error for test binary: %v", err)
+	}
+
+	otherOS := "windows"
+	if runtime.GOOS == "windows" {
+		otherOS = "linux"
+	}
+
+	if err := checkExecutablePlatform(bin, otherOS, runtime.GOARCH); err == nil {
+		t.Errorf("expected error for %s", otherOS)
+	}
+
+	otherArch := "arm64"
+	if runtime.GOARCH == "arm64" {
+		otherArch = "amd64"
+	}
+
+	if err := checkExecutablePlatform(bin, runtime.GOOS, otherArch); err == nil {
+		t.Errorf("expected error for %s", otherArch)
+	}
+}
@@ -7,7 +7,8 @@ import (
 )
 
 type Downloader interface {
-	Download(ctx context.Context, pi *pluginInfo) (*DownloadedPlugin, error)
+	// Download fetches plugin for specified arch, e.g. linux_amd64.
+	Download(ctx context.Context, pi *pluginInfo, arch string) (*DownloadedPlugin, error)
 	MatchingVersion(ctx context.Context, pi *pluginInfo) (matching, latest *semver.Version, err error)
 }
 
@@ -43,7 +43,7 @@ func NewGitHubDownloader() *GitHubDownloader {
 
 var GitHubRegex = regexp.MustCompile(`^https://github\.com/(?P<owner>[^/]+)/(?P<name>[^/]+)/?$`)
 
-func (d *GitHubDownloader) Download(ctx context.Context, pi *pluginInfo) (*DownloadedPlugin, error) {
+func (d *GitHubDownloader) Download(ctx context.Context, pi *pluginInfo, arch string) (*DownloadedPlugin, error) {
 	matches := GitHubRegex.FindStringSubmatch(pi.source)
 	repoOwner := matches[GitHubRegex.SubexpIndex("owner")]
 	repoName := matches[GitHubRegex.SubexpIndex("name")]
@@ -60,8 +60,6 @@ func (d *GitHubDownloader) Download(ctx context.Context, pi *pluginInfo) (*Downl
 
 	var matchingAsset *github.ReleaseAsset
 
-	arch := CurrentArch()
-
 	for _, ass := range rel.Assets {
 		n := ass.GetName()
 
@@ -66,7 +66,8 @@ func (d *VCSDownloader) download(ctx context.Context, pi *pluginInfo) (*Download
 	}, ver.tag, nil
 }
 
-func (d *VCSDownloader) Download(ctx context.Context, pi *pluginInfo) (*DownloadedPlugin, error) {
+// Download fetches repository at matching version, it is the same for every arch.
+func (d *VCSDownloader) Download(ctx context.Context, pi *pluginInfo, _ string) (*DownloadedPlugin, error) {
 	dp, _, err := d.download(ctx, pi)
 
 	return dp, err
@@ -5,4 +5,6 @@ import "errors"
 var (
 	ErrPluginNotFound               = errors.New("plugin not found")
 	ErrPluginNoMatchingVersionFound = errors.New("no matching version for plugin not found")
+	ErrPluginChecksumMismatch       = errors.New("plugin checksum mismatch")
+	ErrPluginDownloadFrozen         = errors.New("plugin downloads are disabled in frozen mode")
 )
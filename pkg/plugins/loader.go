@@ -32,6 +32,8 @@ func CurrentArch() string {
 
 type Loader struct {
 	baseDir, pluginsCacheDir string
+	mirrorDirs               []string
+	frozen                   bool
 
 	downloader struct {
 		vcs    *VCSDownloader
@@ -54,8 +56,21 @@ func NewLoader(baseDir, pluginsCacheDir string) *Loader {
 func (l *Loader) LoadPlugin(ctx context.Context, name, src string, verConstr *semver.Constraints, lock *lockfile.Plugin) (*Plugin, error) {
 	pi := newPluginInfo(name, src, verConstr, lock)
 
+	if IsDevSource(pi.source) {
+		return l.loadDevPlugin(pi)
+	}
+
 	path, ver := l.findInstalledPluginLocation(pi)
 
+	if path == "" {
+		var err error
+
+		path, ver, err = l.installMirroredPlugin(pi)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if path == "" {
 		var err error
 
@@ -75,6 +90,14 @@ func (l *Loader) LoadPlugin(ctx context.Context, name, src string, verConstr *se
 func (l *Loader) DownloadPlugin(ctx context.Context, name string, verConstr *semver.Constraints, src string, lock *lockfile.Plugin) (*Plugin, error) {
 	pi := newPluginInfo(name, src, verConstr, lock)
 
+	if IsDevSource(pi.source) {
+		return l.loadDevPlugin(pi)
+	}
+
+	if l.frozen {
+		return nil, merry.Errorf("%w: cannot download plugin '%s'", ErrPluginDownloadFrozen, name)
+	}
+
 	from, ver, err := l.downloadPlugin(ctx, pi)
 	if err != nil {
 		return nil, err
@@ -163,7 +186,7 @@ func (l *Loader) selectDownloader(src string) Downloader {
 }
 
 func (l *Loader) downloadPlugin(ctx context.Context, pi *pluginInfo) (string, *semver.Version, error) {
-	download, err := l.selectDownloader(pi.source).Download(ctx, pi)
+	download, err := l.selectDownloader(pi.source).Download(ctx, pi, CurrentArch())
 	if err != nil {
 		return "", nil, merry.Errorf("failed to download plugin %s: %w", pi.name, err)
 	}
@@ -255,11 +278,50 @@ func (l *Loader) loadPlugin(pi *pluginInfo, dir string, ver *semver.Version) (*P
 		return nil, merry.Errorf("plugin config load failed.\nfile: %s\n%s", p, err)
 	}
 
+	if IsDevSource(pi.source) {
+		return &plugin, nil
+	}
+
+	// Verify checksum only if lock is for the same version, otherwise record a new one.
+	lock := pi.lock
+	if lock != nil && !lock.Matches(pi.name, ver, pi.source) {
+		lock = nil
+	}
+
+	if err := plugin.verifyChecksum(lock); err != nil {
+		return nil, err
+	}
+
 	return &plugin, nil
 }
 
+// Checksum downloads plugin at exact version for arch, e.g. linux_amd64, and computes checksum of its contents.
+func (l *Loader) Checksum(ctx context.Context, name, src string, ver *semver.Version, arch string) (string, error) {
+	download, err := l.downloadForArch(ctx, name, src, ver, arch)
+	if err != nil {
+		return "", err
+	}
+
+	sum, err := DirChecksum(download.Dir)
+	if err != nil {
+		return "", err
+	}
+
+	if download.TempDir {
+		if err := os.RemoveAll(download.Dir); err != nil {
+			return "", merry.Errorf("failed to remove downloaded plugin temp dir %s: %w", download.Dir, err)
+		}
+	}
+
+	return sum, nil
+}
+
 func (l *Loader) MatchingVersion(ctx context.Context, name, src string, verConstr *semver.Constraints) (matching, latest *semver.Version, err error) {
 	pi := newPluginInfo(name, src, verConstr, nil)
 
+	if IsDevSource(pi.source) {
+		return DevVersion, DevVersion, nil
+	}
+
 	return l.selectDownloader(pi.source).MatchingVersion(ctx, pi)
 }
@@ -0,0 +1,65 @@
+package plugins
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/ansel1/merry/v2"
+)
+
+// DevSourcePrefix marks plugin source as a local working dir used for plugin development.
+const DevSourcePrefix = "file://"
+
+// DevVersion is reported as version of plugins loaded from local working dir.
+var DevVersion = semver.MustParse("0.0.0-dev")
+
+func IsDevSource(src string) bool {
+	return strings.HasPrefix(src, DevSourcePrefix)
+}
+
+// devPluginDir returns plugin dir of dev source, relative paths are resolved against project dir.
+func (l *Loader) devPluginDir(src string) string {
+	dir := filepath.FromSlash(strings.TrimPrefix(src, DevSourcePrefix))
+
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(l.baseDir, dir)
+	}
+
+	return filepath.Clean(dir)
+}
+
+// loadDevPlugin loads plugin directly from its working dir running its build command first if there is one.
+// Dev plugins are never pinned in lockfile.
+func (l *Loader) loadDevPlugin(pi *pluginInfo) (*Plugin, error) {
+	dir := l.devPluginDir(pi.source)
+
+	if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+		return nil, merry.Errorf("dev plugin '%s' dir not found: %s", pi.name, dir)
+	}
+
+	pi.lock = nil
+
+	plugin, err := l.loadPlugin(pi, dir, DevVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	plugin.dev = true
+
+	if plugin.Build == nil || plugin.Build.IsEmpty() {
+		return plugin, nil
+	}
+
+	cmd := plugin.Build.ExecCmdAsUser()
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), fmt.Sprintf("OUTBLOCKS_PLUGIN_DIR=%s", dir))
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, merry.Errorf("dev plugin '%s' build failed: %w\n%s", pi.name, err, out)
+	}
+
+	return plugin, nil
+}
@@ -0,0 +1,54 @@
+package plugins
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/outblocks/outblocks-cli/pkg/lockfile"
+)
+
+func TestLoadDevPlugin(t *testing.T) {
+	t.Parallel()
+
+	base := t.TempDir()
+	dir := filepath.Join(base, "cli-plugin-test")
+
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	err := os.WriteFile(filepath.Join(dir, "plugin.yaml"), []byte(`name: test
+cmd:
+  default: ./plugin
+build: touch built
+actions: [deploy]
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := NewLoader(base, t.TempDir())
+
+	plug, err := l.LoadPlugin(context.Background(), "test", "file://cli-plugin-test", nil, &lockfile.Plugin{Name: "test", Version: DevVersion, Source: "file://cli-plugin-test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !plug.IsDev() || plug.Dir != dir || !plug.Version.Equal(DevVersion) {
+		t.Fatalf("unexpected dev plugin: dev=%v dir=%s version=%s", plug.IsDev(), plug.Dir, plug.Version)
+	}
+
+	if plug.Locked() != nil {
+		t.Fatal("dev plugin should not be pinned in lockfile")
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "built")); err != nil {
+		t.Fatalf("build command was not run: %v", err)
+	}
+
+	if _, err := l.LoadPlugin(context.Background(), "test", "file://missing", nil, nil); err == nil {
+		t.Fatal("expected error for missing dev plugin dir")
+	}
+}
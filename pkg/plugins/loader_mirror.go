@@ -0,0 +1,137 @@
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/ansel1/merry/v2"
+	"github.com/otiai10/copy"
+	"github.com/outblocks/outblocks-cli/internal/fileutil"
+)
+
+// DefaultVendorDir is a dir relative to project dir that plugins are vendored to. It is checked before other mirrors,
+// plugins cache and downloaders, but plugins already installed in project take precedence over it.
+const DefaultVendorDir = "vendor/plugins"
+
+// MirrorDir returns absolute dir of local plugin mirror specified either as a path or file:// URL.
+func MirrorDir(mirror string) (string, error) {
+	dir := strings.TrimPrefix(mirror, DevSourcePrefix)
+
+	if strings.Contains(dir, "://") {
+		return "", merry.Errorf("unsupported plugin mirror '%s', only local dirs and file:// URLs are supported", mirror)
+	}
+
+	return filepath.Abs(filepath.FromSlash(dir))
+}
+
+// WithMirrors adds local mirror dirs, laid out as <dir>/<author>/<name>/<arch>-<version>, that plugins are resolved from before falling back to downloaders.
+func (l *Loader) WithMirrors(dirs ...string) *Loader {
+	l.mirrorDirs = append(l.mirrorDirs, dirs...)
+
+	return l
+}
+
+// WithFrozen disables downloading of plugins, loading plugins that are not available locally fails instead.
+func (l *Loader) WithFrozen(frozen bool) *Loader {
+	l.frozen = frozen
+
+	return l
+}
+
+func (l *Loader) IsFrozen() bool {
+	return l.frozen
+}
+
+func (l *Loader) findMirroredPluginLocation(pi *pluginInfo) (string, *semver.Version) {
+	dirs := append([]string{filepath.Join(l.baseDir, filepath.FromSlash(DefaultVendorDir))}, l.mirrorDirs...)
+
+	for _, dir := range dirs {
+		path, ver := l.findMatchingPluginLocation(pi, filepath.Join(dir, pi.author, pi.name))
+		if path != "" {
+			return path, ver
+		}
+	}
+
+	return "", nil
+}
+
+func (l *Loader) installMirroredPlugin(pi *pluginInfo) (string, *semver.Version, error) {
+	from, ver := l.findMirroredPluginLocation(pi)
+
+	if from == "" {
+		return "", nil, nil
+	}
+
+	if err := l.installPlugin(pi, from); err != nil {
+		return "", nil, err
+	}
+
+	return from, ver, nil
+}
+
+// downloadForArch downloads plugin at exact version for arch.
+func (l *Loader) downloadForArch(ctx context.Context, name, src string, ver *semver.Version, arch string) (*DownloadedPlugin, error) {
+	if l.frozen {
+		return nil, merry.Errorf("%w: cannot download plugin '%s'", ErrPluginDownloadFrozen, name)
+	}
+
+	verConstr, err := semver.NewConstraint(ver.String())
+	if err != nil {
+		return nil, err
+	}
+
+	pi := newPluginInfo(name, src, verConstr, nil)
+
+	download, err := l.selectDownloader(pi.source).Download(ctx, pi, arch)
+	if err != nil {
+		return nil, merry.Errorf("failed to download plugin %s for %s: %w", pi.name, arch, err)
+	}
+
+	if !download.Version.Equal(ver) {
+		return nil, merry.Errorf("failed to download plugin %s for %s: expected version %s, got %s", pi.name, arch, ver, download.Version)
+	}
+
+	return download, nil
+}
+
+// VendorPlugin copies plugin for arch to dir laid out as a mirror and returns checksum of its contents.
+// Plugin for current arch is copied from where it is loaded from, for other archs it is downloaded.
+func (l *Loader) VendorPlugin(ctx context.Context, plug *Plugin, name, src, arch, dir string) (string, error) {
+	pi := newPluginInfo(name, src, nil, nil)
+	from := plug.Dir
+
+	if arch != CurrentArch() {
+		download, err := l.downloadForArch(ctx, name, src, plug.Version, arch)
+		if err != nil {
+			return "", err
+		}
+
+		if download.TempDir {
+			defer os.RemoveAll(download.Dir) //nolint:errcheck
+		}
+
+		from = download.Dir
+	}
+
+	dest := filepath.Join(dir, pi.author, pi.name, fmt.Sprintf("%s-%s", arch, plug.Version))
+	_ = os.RemoveAll(dest)
+
+	if err := fileutil.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return "", merry.Errorf("failed to create dir %s: %w", dest, err)
+	}
+
+	err := copy.Copy(from, dest, copy.Options{
+		Skip: func(src string) (bool, error) {
+			return checksumSkipDirs[filepath.Base(src)], nil
+		},
+	})
+	if err != nil {
+		return "", merry.Errorf("failed to copy plugin %s to %s: %w", name, dest, err)
+	}
+
+	return DirChecksum(dest)
+}
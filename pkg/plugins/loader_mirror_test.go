@@ -0,0 +1,71 @@
+package plugins
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadPluginFromMirror(t *testing.T) {
+	t.Parallel()
+
+	base := t.TempDir()
+	mirror := t.TempDir()
+
+	writePluginDir(t, filepath.Join(mirror, "outblocks", "test", CurrentArch()+"-1.2.0"), "v1")
+
+	l := NewLoader(base, t.TempDir()).WithFrozen(true)
+
+	if _, err := l.LoadPlugin(context.Background(), "test", "", nil, nil); !errors.Is(err, ErrPluginNotFound) {
+		t.Fatalf("expected plugin not to be found without mirror, got: %v", err)
+	}
+
+	if _, err := l.DownloadPlugin(context.Background(), "test", nil, "", nil); !errors.Is(err, ErrPluginDownloadFrozen) {
+		t.Fatalf("expected download to fail in frozen mode, got: %v", err)
+	}
+
+	dir, err := MirrorDir("file://" + filepath.ToSlash(mirror))
+	if err != nil || dir != mirror {
+		t.Fatalf("unexpected mirror dir: %s, %v", dir, err)
+	}
+
+	if _, err := MirrorDir("https://example.com/plugins"); err == nil {
+		t.Fatal("expected error for remote mirror")
+	}
+
+	plug, err := l.WithMirrors(dir).LoadPlugin(context.Background(), "test", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if plug.Version.String() != "1.2.0" {
+		t.Fatalf("unexpected plugin version: %s", plug.Version)
+	}
+
+	vendorDir := filepath.Join(base, DefaultVendorDir)
+
+	sum, err := l.VendorPlugin(context.Background(), plug, "test", "", CurrentArch(), vendorDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sum != plug.Checksum(CurrentArch()) {
+		t.Fatalf("vendored plugin checksum %s differs from loaded %s", sum, plug.Checksum(CurrentArch()))
+	}
+
+	vendored := filepath.Join(vendorDir, "outblocks", "test", CurrentArch()+"-1.2.0")
+
+	if _, err := os.Stat(filepath.Join(vendored, ".git")); !os.IsNotExist(err) {
+		t.Fatal("expected VCS metadata not to be vendored")
+	}
+
+	// Vendored plugins are loaded without any mirror configured.
+	base2 := t.TempDir()
+	writePluginDir(t, filepath.Join(base2, DefaultVendorDir, "outblocks", "test", CurrentArch()+"-1.2.0"), "v1")
+
+	if _, err := NewLoader(base2, t.TempDir()).WithFrozen(true).LoadPlugin(context.Background(), "test", "", nil, nil); err != nil {
+		t.Fatalf("unexpected error loading vendored plugin: %v", err)
+	}
+}
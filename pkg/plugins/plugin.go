@@ -9,6 +9,7 @@ import (
 
 	"github.com/23doors/go-yaml"
 	"github.com/Masterminds/semver"
+	"github.com/ansel1/merry/v2"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/outblocks/outblocks-cli/pkg/lockfile"
 	"github.com/outblocks/outblocks-cli/pkg/logger"
@@ -34,16 +35,19 @@ type Plugin struct {
 	Commands       map[string]*PluginCommand         `json:"commands"`
 	SecretsRepr    yaml.MapSlice                     `json:"secrets"`
 	AppOverrides   AppOverrides                      `json:"app_overrides"`
-
-	Dir      string          `json:"-"`
-	CacheDir string          `json:"-"`
-	Version  *semver.Version `json:"-"`
-	yamlPath string
-	yamlData []byte
-	source   string
-	actions  []Action
-	client   *client.Client
-	secrets  []*PluginSecret
+	Build          *command.StringCommand            `json:"build"`
+
+	Dir       string          `json:"-"`
+	CacheDir  string          `json:"-"`
+	Version   *semver.Version `json:"-"`
+	yamlPath  string
+	yamlData  []byte
+	source    string
+	dev       bool
+	checksums map[string]string
+	actions   []Action
+	client    *client.Client
+	secrets   []*PluginSecret
 }
 
 type Action int
@@ -111,14 +115,64 @@ func (p *Plugin) Secrets() []*PluginSecret {
 	return p.secrets
 }
 
+// Locked returns lockfile entry of plugin or nil for dev plugins as they are never pinned,
+// lockfile entry they override is kept as is.
 func (p *Plugin) Locked() *lockfile.Plugin {
+	if p.dev {
+		return nil
+	}
+
 	return &lockfile.Plugin{
-		Name:    p.Name,
-		Version: p.Version,
-		Source:  p.source,
+		Name:      p.Name,
+		Version:   p.Version,
+		Source:    p.source,
+		Checksums: p.checksums,
 	}
 }
 
+// verifyChecksum computes checksum of plugin dir and compares it with one recorded in lock for current arch.
+func (p *Plugin) verifyChecksum(lock *lockfile.Plugin) error {
+	sum, err := DirChecksum(p.Dir)
+	if err != nil {
+		return err
+	}
+
+	p.checksums = make(map[string]string)
+
+	if lock != nil {
+		for k, v := range lock.Checksums {
+			p.checksums[k] = v
+		}
+	}
+
+	return p.SetChecksum(CurrentArch(), sum)
+}
+
+// Checksum returns recorded checksum of plugin for arch.
+func (p *Plugin) Checksum(arch string) string {
+	return p.checksums[arch]
+}
+
+// SetChecksum records checksum of plugin for arch, it fails if a different checksum was already recorded.
+func (p *Plugin) SetChecksum(arch, sum string) error {
+	if expected, ok := p.checksums[arch]; ok && expected != sum {
+		return merry.Errorf("%w: plugin '%s' %s for %s has checksum %s, expected %s\nplugin was modified or its release was re-published, if this is expected remove its checksums from outblocks.lock", ErrPluginChecksumMismatch, p.Name, p.Version, arch, sum, expected)
+	}
+
+	if p.checksums == nil {
+		p.checksums = make(map[string]string)
+	}
+
+	p.checksums[arch] = sum
+
+	return nil
+}
+
+// IsDev returns true if plugin is loaded from a local working dir.
+func (p *Plugin) IsDev() bool {
+	return p.dev
+}
+
 func (p *Plugin) ShortName() string {
 	if p.Short != "" {
 		return p.Short
@@ -184,13 +238,27 @@ func (p *Plugin) SupportsApp(app string) bool {
 	return false
 }
 
-func (p *Plugin) Prepare(ctx context.Context, log logger.Logger, env, projectID, projectName, projectDir, hostAddr string, props map[string]interface{}, yamlPrefix string, yamlData []byte) error {
-	runCommand, ok := p.Cmd[runtime.GOOS]
-	if !ok {
-		runCommand = p.Cmd["default"]
+// HostTokenEnv is an env var with host token that plugins declaring "host_token" in handshake have to send with every call to host server.
+const HostTokenEnv = "OUTBLOCKS_HOST_TOKEN"
+
+// Host is a host gRPC server that plugins connect to.
+type Host interface {
+	client.Host
+
+	// Token returns host token that plugins supporting it have to send with every call to host server.
+	Token() string
+}
+
+func (p *Plugin) runCommand() *command.StringCommand {
+	if runCommand, ok := p.Cmd[runtime.GOOS]; ok {
+		return runCommand
 	}
 
-	cmd := runCommand.ExecCmdAsUser()
+	return p.Cmd["default"]
+}
+
+func (p *Plugin) Prepare(ctx context.Context, log logger.Logger, env, projectID, projectName, projectDir string, host Host, props map[string]interface{}, yamlPrefix string, yamlData []byte) error {
+	cmd := p.runCommand().ExecCmdAsUser()
 
 	cmd.Env = append(os.Environ(),
 		fmt.Sprintf("OUTBLOCKS_BIN=%s", os.Args[0]),
@@ -200,11 +268,12 @@ func (p *Plugin) Prepare(ctx context.Context, log logger.Logger, env, projectID,
 		fmt.Sprintf("OUTBLOCKS_PROJECT_NAME=%s", projectName),
 		fmt.Sprintf("OUTBLOCKS_PROJECT_ID=%s", projectID),
 		fmt.Sprintf("OUTBLOCKS_PROJECT_DIR=%s", projectDir),
+		fmt.Sprintf("%s=%s", HostTokenEnv, host.Token()),
 	)
 
 	var err error
 
-	p.client, err = client.NewClient(log, p.Name, env, cmd, hostAddr, props, client.YAMLContext{
+	p.client, err = client.NewClient(log, p.Name, env, cmd, host, props, client.YAMLContext{
 		Prefix: yamlPrefix,
 		Data:   yamlData,
 	})
@@ -0,0 +1,219 @@
+package plugins
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/ansel1/merry/v2"
+	"github.com/outblocks/outblocks-cli/pkg/lockfile"
+)
+
+// PrunedPlugin is a plugin dir or symlink removed from plugins cache or project.
+type PrunedPlugin struct {
+	Path   string
+	Size   int64
+	Reason string
+}
+
+type PruneOptions struct {
+	// Keep are plugin versions that are not removed.
+	Keep []*lockfile.Plugin
+	// AllPlugins prunes cached versions of all plugins, not only of the ones in Keep.
+	AllPlugins bool
+	DryRun     bool
+}
+
+type pruneKeepSet map[string]map[string]bool
+
+func newPruneKeepSet(keep []*lockfile.Plugin) pruneKeepSet {
+	set := make(pruneKeepSet)
+
+	for _, p := range keep {
+		if p == nil || p.Version == nil {
+			continue
+		}
+
+		a, n := author(p.Name)
+		key := a + "/" + n
+
+		if set[key] == nil {
+			set[key] = make(map[string]bool)
+		}
+
+		set[key][p.Version.String()] = true
+	}
+
+	return set
+}
+
+// Prune removes cached plugin versions that are not kept along with broken and stale plugin links in project.
+func (l *Loader) Prune(opts *PruneOptions) ([]*PrunedPlugin, error) {
+	keep := newPruneKeepSet(opts.Keep)
+
+	var pruned []*PrunedPlugin
+
+	cached, err := prunePluginsDir(l.pluginsCacheDir, keep, opts.AllPlugins, opts.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	pruned = append(pruned, cached...)
+
+	installed, err := prunePluginsDir(filepath.Join(l.baseDir, ".outblocks", "plugins"), keep, true, opts.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	pruned = append(pruned, installed...)
+
+	return pruned, nil
+}
+
+// prunePluginsDir prunes plugins dir laid out as <dir>/<author>/<name>/<arch>-<version>.
+func prunePluginsDir(dir string, keep pruneKeepSet, all, dryRun bool) ([]*PrunedPlugin, error) {
+	var pruned []*PrunedPlugin
+
+	authors, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, merry.Errorf("cannot read plugins dir %s: %w", dir, err)
+	}
+
+	for _, a := range authors {
+		if !a.IsDir() {
+			continue
+		}
+
+		names, err := os.ReadDir(filepath.Join(dir, a.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, n := range names {
+			if !n.IsDir() {
+				continue
+			}
+
+			versions, ok := keep[a.Name()+"/"+n.Name()]
+			if !ok && !all {
+				continue
+			}
+
+			pluginDir := filepath.Join(dir, a.Name(), n.Name())
+
+			entries, err := os.ReadDir(pluginDir)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, e := range entries {
+				path := filepath.Join(pluginDir, e.Name())
+
+				reason := pruneReason(path, e, versions)
+				if reason == "" {
+					continue
+				}
+
+				size, err := pathSize(path, e)
+				if err != nil {
+					return nil, err
+				}
+
+				pruned = append(pruned, &PrunedPlugin{
+					Path:   path,
+					Size:   size,
+					Reason: reason,
+				})
+
+				if dryRun {
+					continue
+				}
+
+				if err := os.RemoveAll(path); err != nil {
+					return nil, merry.Errorf("cannot remove %s: %w", path, err)
+				}
+			}
+
+			if !dryRun {
+				removeIfEmpty(pluginDir)
+			}
+		}
+
+		if !dryRun {
+			removeIfEmpty(filepath.Join(dir, a.Name()))
+		}
+	}
+
+	sort.Slice(pruned, func(i, j int) bool {
+		return pruned[i].Path < pruned[j].Path
+	})
+
+	return pruned, nil
+}
+
+func pruneReason(path string, e fs.DirEntry, versions map[string]bool) string {
+	if e.Type()&fs.ModeSymlink != 0 {
+		if _, err := filepath.EvalSymlinks(path); err != nil {
+			return "broken link"
+		}
+	}
+
+	parts := strings.SplitN(e.Name(), "-", 2)
+	if len(parts) != 2 {
+		return ""
+	}
+
+	ver, err := semver.NewVersion(parts[1])
+	if err != nil {
+		return ""
+	}
+
+	if versions[ver.String()] {
+		return ""
+	}
+
+	return "not locked"
+}
+
+func pathSize(path string, e fs.DirEntry) (int64, error) {
+	if e.Type()&fs.ModeSymlink != 0 {
+		return 0, nil
+	}
+
+	var size int64
+
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		size += info.Size()
+
+		return nil
+	})
+
+	return size, err
+}
+
+func removeIfEmpty(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err == nil && len(entries) == 0 {
+		_ = os.Remove(dir)
+	}
+}
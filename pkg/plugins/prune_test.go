@@ -0,0 +1,76 @@
+package plugins
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/outblocks/outblocks-cli/pkg/lockfile"
+)
+
+func TestPrune(t *testing.T) {
+	t.Parallel()
+
+	base, cache := t.TempDir(), t.TempDir()
+	arch := CurrentArch()
+
+	for _, d := range []string{
+		"outblocks/gcp/" + arch + "-1.0.0",
+		"outblocks/gcp/" + arch + "-1.1.0",
+		"outblocks/gcp/darwin_arm64-1.1.0",
+		"outblocks/other/" + arch + "-0.1.0",
+	} {
+		writePluginDir(t, filepath.Join(cache, d), "bin")
+	}
+
+	installed := filepath.Join(base, ".outblocks", "plugins", "outblocks", "gcp")
+	if err := os.MkdirAll(installed, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Symlink(filepath.Join(cache, "outblocks/gcp", arch+"-1.1.0"), filepath.Join(installed, arch+"-1.1.0")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Symlink(filepath.Join(cache, "missing"), filepath.Join(installed, arch+"-0.9.0")); err != nil {
+		t.Fatal(err)
+	}
+
+	keep := []*lockfile.Plugin{{Name: "gcp", Version: semver.MustParse("1.1.0")}}
+	l := NewLoader(base, cache)
+
+	pruned, err := l.Prune(&PruneOptions{Keep: keep, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pruned) != 2 || pruned[0].Path != filepath.Join(cache, "outblocks/gcp", arch+"-1.0.0") || pruned[0].Size == 0 || pruned[1].Reason != "broken link" {
+		t.Fatalf("unexpected dry run result: %+v", pruned)
+	}
+
+	if _, err := os.Stat(filepath.Join(cache, "outblocks/gcp", arch+"-1.0.0")); err != nil {
+		t.Fatal("dry run should not remove anything")
+	}
+
+	pruned, err = l.Prune(&PruneOptions{Keep: keep, AllPlugins: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pruned) != 3 {
+		t.Fatalf("unexpected prune result: %+v", pruned)
+	}
+
+	for _, d := range []string{"outblocks/gcp/" + arch + "-1.0.0", "outblocks/other"} {
+		if _, err := os.Stat(filepath.Join(cache, d)); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed", d)
+		}
+	}
+
+	for _, p := range []string{filepath.Join(cache, "outblocks/gcp/darwin_arm64-1.1.0"), filepath.Join(installed, arch+"-1.1.0")} {
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("expected %s to be kept: %v", p, err)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/23doors/go-yaml"
+	"github.com/ansel1/merry/v2"
+)
+
+const answerEnvPrefix = "OUTBLOCKS_ANSWER_"
+
+var promptKeyReplacer = regexp.MustCompile(`[^A-Z0-9]+`)
+
+type answer struct {
+	key   string
+	regex *regexp.Regexp
+	value interface{}
+}
+
+// Answers holds predefined answers for plugin prompts, keyed by prompt message, its key or /regex/ matching prompt message.
+type Answers struct {
+	file    string
+	entries []*answer
+}
+
+// LoadAnswers loads answers from YAML file.
+func LoadAnswers(file string) (*Answers, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, merry.Errorf("cannot read answers file: %w", err)
+	}
+
+	var m yaml.MapSlice
+
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, merry.Errorf("cannot parse answers file %s:\n%s", file, yaml.FormatErrorDefault(err))
+	}
+
+	a := &Answers{
+		file: file,
+	}
+
+	for _, item := range m {
+		key := fmt.Sprint(item.Key)
+		ans := &answer{
+			key:   key,
+			value: item.Value,
+		}
+
+		if len(key) > 1 && strings.HasPrefix(key, "/") && strings.HasSuffix(key, "/") {
+			ans.regex, err = regexp.Compile(key[1 : len(key)-1])
+			if err != nil {
+				return nil, merry.Errorf("invalid answer pattern '%s' in %s: %w", key, file, err)
+			}
+		}
+
+		a.entries = append(a.entries, ans)
+	}
+
+	return a, nil
+}
+
+// PromptKey returns stable key of prompt derived from its message, e.g. CREATE_BUCKET for "Create bucket?".
+func PromptKey(message string) string {
+	return strings.Trim(promptKeyReplacer.ReplaceAllString(strings.ToUpper(message), "_"), "_")
+}
+
+// AnswerEnvVar returns env var name that answers prompt with given message.
+func AnswerEnvVar(message string) string {
+	return answerEnvPrefix + PromptKey(message)
+}
+
+func (a *Answers) lookup(message string) (val interface{}, source string, ok bool) {
+	env := AnswerEnvVar(message)
+	if v, ok := os.LookupEnv(env); ok {
+		return v, env, true
+	}
+
+	if a == nil {
+		return nil, "", false
+	}
+
+	// Exact matches take precedence over patterns.
+	for _, e := range a.entries {
+		if e.regex == nil && (e.key == message || PromptKey(e.key) == PromptKey(message)) {
+			return e.value, a.file, true
+		}
+	}
+
+	for _, e := range a.entries {
+		if e.regex != nil && e.regex.MatchString(message) {
+			return e.value, a.file, true
+		}
+	}
+
+	return nil, "", false
+}
+
+func answerString(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+
+	return fmt.Sprint(v)
+}
+
+func answerBool(v interface{}) (bool, error) {
+	if b, ok := v.(bool); ok {
+		return b, nil
+	}
+
+	switch strings.ToLower(answerString(v)) {
+	case "y", "yes", "true", "1":
+		return true, nil
+	case "n", "no", "false", "0":
+		return false, nil
+	}
+
+	return false, merry.Errorf("invalid confirmation answer '%v', expected yes or no", v)
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/outblocks/outblocks-cli/pkg/logger"
+	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAnswers(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "answers.yaml")
+
+	err := os.WriteFile(file, []byte(`create_bucket: yes
+"Select region": europe-west1
+/^Create project .*\?$/: no
+db-password: hunter22
+`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers, err := LoadAnswers(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewServer(logger.NewLogger(), nil)
+	s.SetAnswers(answers)
+
+	ctx := context.Background()
+
+	conf, err := s.PromptConfirmation(ctx, &apiv1.PromptConfirmationRequest{Message: "Create bucket?"})
+	if err != nil || !conf.Confirmed {
+		t.Fatalf("unexpected confirmation answer by key: %v, %v", conf, err)
+	}
+
+	conf, err = s.PromptConfirmation(context.Background(), &apiv1.PromptConfirmationRequest{Message: "Create project my-project?", Default: true})
+	if err != nil || conf.Confirmed {
+		t.Fatalf("unexpected confirmation answer by pattern: %v, %v", conf, err)
+	}
+
+	sel, err := s.PromptSelect(context.Background(), &apiv1.PromptSelectRequest{Message: "Select region", Options: []string{"us-central1", "europe-west1"}})
+	if err != nil || sel.Answer != "europe-west1" {
+		t.Fatalf("unexpected select answer: %v, %v", sel, err)
+	}
+
+	_, err = s.PromptSelect(context.Background(), &apiv1.PromptSelectRequest{Message: "Select region", Options: []string{"us-central1"}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument for answer out of options, got: %v", err)
+	}
+
+	t.Setenv("OUTBLOCKS_ANSWER_DB_PASSWORD", "from-env")
+
+	input, err := s.PromptInput(ctx, &apiv1.PromptInputRequest{Message: "DB password:"})
+	if err != nil || input.Answer != "from-env" {
+		t.Fatalf("unexpected input answer from env: %v, %v", input, err)
+	}
+
+	// Missing answer fails instead of being reported as canceled prompt (Aborted).
+	_, err = s.PromptInput(ctx, &apiv1.PromptInputRequest{Message: "Unknown question?"})
+	if status.Code(err) != codes.FailedPrecondition || !strings.Contains(err.Error(), "Unknown question?") || !strings.Contains(err.Error(), "OUTBLOCKS_ANSWER_UNKNOWN_QUESTION env var") {
+		t.Fatalf("expected missing answer error with prompt text, got: %v", err)
+	}
+}
+
+func TestPromptKey(t *testing.T) {
+	tests := map[string]string{
+		"Create bucket?":                   "CREATE_BUCKET",
+		"  Select GCP region (default):  ": "SELECT_GCP_REGION_DEFAULT",
+		"create-bucket":                    "CREATE_BUCKET",
+	}
+
+	for in, want := range tests {
+		if got := PromptKey(in); got != want {
+			t.Errorf("PromptKey(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	if got := AnswerEnvVar("Create bucket?"); got != "OUTBLOCKS_ANSWER_CREATE_BUCKET" {
+		t.Errorf("unexpected env var: %s", got)
+	}
+}
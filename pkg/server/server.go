@@ -2,8 +2,16 @@ package server
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/AlecAivazis/survey/v2/terminal"
@@ -11,52 +19,269 @@ import (
 	"github.com/outblocks/outblocks-cli/internal/util"
 	"github.com/outblocks/outblocks-cli/pkg/logger"
 	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
+	"golang.org/x/exp/slices"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
+// TokenMetadataKey is a gRPC metadata key that plugins declaring support for it have to set to host token on every call.
+const TokenMetadataKey = "outblocks-host-token"
+
 type Server struct {
 	srv     *grpc.Server
 	secrets map[string]interface{}
 	log     logger.Logger
+	token   string
+
+	answers *Answers
+
+	secretsFilterMu sync.RWMutex
+	secretsFilter   func(appID, key string) bool
+
+	endpointsMu sync.RWMutex
+	endpoints   map[string]*endpoint
+	tempDir     string
+
+	// scopes maps plugin name to IDs of apps it currently operates on with count of calls in progress for each.
+	scopesMu sync.Mutex
+	scopes   map[string]map[string]int
+}
+
+type endpointContextKey struct{}
 
-	addr net.Addr
+// endpoint is a listener dedicated to a single plugin, calls are attributed to plugin by endpoint they arrive on.
+type endpoint struct {
+	plugin       string
+	requireToken bool
 }
 
 func NewServer(log logger.Logger, secrets map[string]interface{}) *Server {
 	return &Server{
-		log:     log,
-		secrets: secrets,
+		log:       log,
+		secrets:   secrets,
+		endpoints: make(map[string]*endpoint),
+		scopes:    make(map[string]map[string]int),
+	}
+}
+
+// Token returns random per session token that is required on calls from plugins that support it.
+func (s *Server) Token() string {
+	return s.token
+}
+
+// SetSecretsFilter sets function deciding if secret key may be returned to plugin operating on app with given ID.
+// Filter is called with empty app ID for keys requested outside of scope of any app.
+func (s *Server) SetSecretsFilter(f func(appID, key string) bool) {
+	s.secretsFilterMu.Lock()
+	defer s.secretsFilterMu.Unlock()
+
+	s.secretsFilter = f
+}
+
+// BeginScope marks apps that plugin operates on during a call, e.g. plan or apply of these apps.
+// Until returned function is called, plugin may get secrets scoped to any of them.
+func (s *Server) BeginScope(plugin string, appIDs []string) (end func()) {
+	s.scopesMu.Lock()
+	defer s.scopesMu.Unlock()
+
+	scope := s.scopes[plugin]
+	if scope == nil {
+		scope = make(map[string]int)
+		s.scopes[plugin] = scope
+	}
+
+	for _, id := range appIDs {
+		scope[id]++
+	}
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			s.scopesMu.Lock()
+			defer s.scopesMu.Unlock()
+
+			for _, id := range appIDs {
+				scope[id]--
+
+				if scope[id] <= 0 {
+					delete(scope, id)
+				}
+			}
+		})
+	}
+}
+
+func (s *Server) scopeAppIDs(plugin string) []string {
+	s.scopesMu.Lock()
+	defer s.scopesMu.Unlock()
+
+	ids := make([]string, 0, len(s.scopes[plugin]))
+
+	for id := range s.scopes[plugin] {
+		ids = append(ids, id)
 	}
+
+	return ids
 }
 
-func (s *Server) Addr() net.Addr {
-	return s.addr
+// isSecretAllowed checks if secret key is available to calling plugin. Plugin is identified by endpoint it called,
+// apps it operates on are tracked by host with BeginScope so that they cannot be spoofed by plugin.
+func (s *Server) isSecretAllowed(ctx context.Context, key string) bool {
+	s.secretsFilterMu.RLock()
+	defer s.secretsFilterMu.RUnlock()
+
+	if s.secretsFilter == nil || s.secretsFilter("", key) {
+		return true
+	}
+
+	ep, ok := ctx.Value(endpointContextKey{}).(*endpoint)
+	if !ok {
+		return false
+	}
+
+	for _, id := range s.scopeAppIDs(ep.plugin) {
+		if s.secretsFilter(id, key) {
+			return true
+		}
+	}
+
+	return false
 }
 
+// Serve starts server, plugins connect to it through endpoints created with Endpoint.
 func (s *Server) Serve() error {
-	l, err := net.Listen("tcp4", "")
-	if err != nil {
-		return err
+	token := make([]byte, 32)
+
+	if _, err := rand.Read(token); err != nil {
+		return merry.Errorf("cannot generate host token: %w", err)
+	}
+
+	s.token = hex.EncodeToString(token)
+
+	// Private dir for unix sockets, loopback interface is used if it cannot be created.
+	if dir, err := os.MkdirTemp("", "outblocks-"); err == nil {
+		s.tempDir = dir
 	}
 
-	s.srv = grpc.NewServer()
+	s.srv = grpc.NewServer(grpc.UnaryInterceptor(s.authInterceptor))
 	apiv1.RegisterHostServiceServer(s.srv, s)
 
-	s.addr = l.Addr()
+	return nil
+}
+
+// Endpoint starts listening on a unix socket in a private temp dir dedicated to plugin, falling back to loopback interface
+// if unix sockets are not available, and returns gRPC target that plugin should dial.
+// Host token is required on calls if requireToken is set, plugins declare support for sending it in handshake.
+// Loopback interface is reachable by any local user, so plugins not supporting host token are never served on it.
+func (s *Server) Endpoint(plugin string, requireToken bool) (string, error) {
+	s.endpointsMu.Lock()
+	defer s.endpointsMu.Unlock()
+
+	l, target, err := s.listen(len(s.endpoints), requireToken)
+	if err != nil {
+		return "", merry.Errorf("cannot listen for plugin '%s': %w", plugin, err)
+	}
+
+	s.endpoints[l.Addr().String()] = &endpoint{
+		plugin:       plugin,
+		requireToken: requireToken,
+	}
 
 	go func() {
 		_ = s.srv.Serve(l)
 	}()
 
-	return nil
+	return target, nil
+}
+
+func (s *Server) listen(i int, requireToken bool) (l net.Listener, target string, err error) {
+	if s.tempDir != "" {
+		sock := filepath.Join(s.tempDir, fmt.Sprintf("host-%d.sock", i))
+
+		l, err := net.Listen("unix", sock)
+		if err == nil {
+			return l, "unix://" + sock, nil
+		}
+	}
+
+	if !requireToken {
+		return nil, "", merry.New("unix sockets are not available and plugin does not support host token, update plugin to newer version")
+	}
+
+	l, err = net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		return nil, "", err
+	}
+
+	return l, l.Addr().String(), nil
+}
+
+func (s *Server) endpoint(ctx context.Context) *endpoint {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.LocalAddr == nil {
+		return nil
+	}
+
+	s.endpointsMu.RLock()
+	defer s.endpointsMu.RUnlock()
+
+	return s.endpoints[p.LocalAddr.String()]
+}
+
+func (s *Server) authInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ep := s.endpoint(ctx)
+	if ep == nil {
+		return nil, status.Error(codes.Unauthenticated, "unknown host endpoint")
+	}
+
+	if ep.requireToken {
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		if v := md.Get(TokenMetadataKey); len(v) == 0 || subtle.ConstantTimeCompare([]byte(v[0]), []byte(s.token)) != 1 {
+			return nil, status.Error(codes.Unauthenticated, "invalid host token")
+		}
+	}
+
+	return handler(context.WithValue(ctx, endpointContextKey{}, ep), req)
 }
 
 func (s *Server) Stop() {
 	if s.srv != nil {
 		s.srv.GracefulStop()
 	}
+
+	if s.tempDir != "" {
+		_ = os.RemoveAll(s.tempDir)
+	}
+}
+
+// SetAnswers sets predefined answers used for plugin prompts.
+func (s *Server) SetAnswers(a *Answers) {
+	s.answers = a
+}
+
+// answer returns predefined answer for prompt. If there is none and prompting is not possible, an error is returned.
+func (s *Server) answer(message string) (val interface{}, ok bool, err error) {
+	val, source, ok := s.answers.lookup(message)
+	if ok {
+		// Answer value is not logged as it may be sensitive.
+		s.log.Infof("Answered prompt '%s' non-interactively (from %s).\n", message, source)
+
+		return val, true, nil
+	}
+
+	if !util.IsTerminal() {
+		// Aborted is reserved for prompts canceled by user, which callers treat as a graceful cancel.
+		// Missing answer has to fail instead so that non-interactive runs do not silently succeed.
+		return nil, false, status.Errorf(codes.FailedPrecondition, "no answer for prompt in non-interactive mode: %s\nprovide it with --answers file (key: %s) or %s env var",
+			message, PromptKey(message), AnswerEnvVar(message))
+	}
+
+	return nil, false, nil
 }
 
 func mapPromptError(err error) error {
@@ -68,13 +293,25 @@ func mapPromptError(err error) error {
 }
 
 func (s *Server) PromptConfirmation(ctx context.Context, r *apiv1.PromptConfirmationRequest) (*apiv1.PromptConfirmationResponse, error) {
-	if !util.IsTerminal() {
-		return nil, status.New(codes.Aborted, terminal.InterruptErr.Error()).Err()
+	val, ok, err := s.answer(r.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		confirmed, err := answerBool(val)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		return &apiv1.PromptConfirmationResponse{
+			Confirmed: confirmed,
+		}, nil
 	}
 
 	confirmed := r.Default
 
-	err := survey.AskOne(&survey.Confirm{
+	err = survey.AskOne(&survey.Confirm{
 		Message: r.Message,
 	}, &confirmed)
 	if err != nil {
@@ -87,13 +324,20 @@ func (s *Server) PromptConfirmation(ctx context.Context, r *apiv1.PromptConfirma
 }
 
 func (s *Server) PromptInput(ctx context.Context, r *apiv1.PromptInputRequest) (*apiv1.PromptInputResponse, error) {
-	if !util.IsTerminal() {
-		return nil, status.New(codes.Aborted, terminal.InterruptErr.Error()).Err()
+	val, ok, err := s.answer(r.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		return &apiv1.PromptInputResponse{
+			Answer: answerString(val),
+		}, nil
 	}
 
 	var input string
 
-	err := survey.AskOne(&survey.Input{
+	err = survey.AskOne(&survey.Input{
 		Message: r.Message,
 		Default: r.Default,
 	}, &input)
@@ -107,8 +351,20 @@ func (s *Server) PromptInput(ctx context.Context, r *apiv1.PromptInputRequest) (
 }
 
 func (s *Server) PromptSelect(ctx context.Context, r *apiv1.PromptSelectRequest) (*apiv1.PromptSelectResponse, error) {
-	if !util.IsTerminal() {
-		return nil, status.New(codes.Aborted, terminal.InterruptErr.Error()).Err()
+	val, ok, err := s.answer(r.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		input := answerString(val)
+		if !slices.Contains(r.Options, input) {
+			return nil, status.Errorf(codes.InvalidArgument, "answer '%s' for prompt '%s' is not one of options: %s", input, r.Message, strings.Join(r.Options, ", "))
+		}
+
+		return &apiv1.PromptSelectResponse{
+			Answer: input,
+		}, nil
 	}
 
 	var input string
@@ -122,7 +378,7 @@ func (s *Server) PromptSelect(ctx context.Context, r *apiv1.PromptSelectRequest)
 		sel.Default = r.Default
 	}
 
-	err := survey.AskOne(sel, &input)
+	err = survey.AskOne(sel, &input)
 	if err != nil {
 		return nil, mapPromptError(err)
 	}
@@ -160,6 +416,12 @@ func (s *Server) HostGetSecret(ctx context.Context, r *apiv1.HostGetSecretReques
 		return &apiv1.HostGetSecretResponse{Specified: false}, nil
 	}
 
+	if !s.isSecretAllowed(ctx, r.Key) {
+		s.log.Debugf("Secret '%s' requested by plugin is out of scope, ignoring.\n", r.Key)
+
+		return &apiv1.HostGetSecretResponse{Specified: false}, nil
+	}
+
 	v, ok := s.secrets[r.Key]
 
 	var vstr string
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/outblocks/outblocks-cli/pkg/logger"
+	apiv1 "github.com/outblocks/outblocks-plugin-go/gen/api/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func dialEndpoint(t *testing.T, s *Server, plugin string, requireToken bool) apiv1.HostServiceClient {
+	t.Helper()
+
+	addr, err := s.Endpoint(plugin, requireToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(addr, "unix://") && !strings.HasPrefix(addr, "127.0.0.1:") {
+		t.Fatalf("server listens on non-local address: %s", addr)
+	}
+
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return apiv1.NewHostServiceClient(conn)
+}
+
+func TestServerEndpoints(t *testing.T) {
+	t.Parallel()
+
+	s := NewServer(logger.NewLogger(), map[string]interface{}{"key": "value"})
+
+	if err := s.Serve(); err != nil {
+		t.Fatal(err)
+	}
+
+	defer s.Stop()
+
+	if len(s.Token()) != 64 {
+		t.Fatalf("unexpected token: %q", s.Token())
+	}
+
+	ctx := context.Background()
+	cli := dialEndpoint(t, s, "secure", true)
+
+	for _, token := range []string{"", "invalid"} {
+		callCtx := ctx
+		if token != "" {
+			callCtx = metadata.AppendToOutgoingContext(ctx, TokenMetadataKey, token)
+		}
+
+		_, err := cli.HostGetSecret(callCtx, &apiv1.HostGetSecretRequest{Key: "key"})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Fatalf("expected unauthenticated error for token %q, got: %v", token, err)
+		}
+	}
+
+	res, err := cli.HostGetSecret(metadata.AppendToOutgoingContext(ctx, TokenMetadataKey, s.Token()), &apiv1.HostGetSecretRequest{Key: "key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !res.Specified || res.Value != "value" {
+		t.Fatalf("unexpected response: %+v", res)
+	}
+
+	// Plugins that do not support host token are served on their own endpoint without it.
+	res, err = dialEndpoint(t, s, "legacy", false).HostGetSecret(ctx, &apiv1.HostGetSecretRequest{Key: "key"})
+	if err != nil {
+		t.Fatalf("unexpected error for plugin without token support: %v", err)
+	}
+
+	if !res.Specified || res.Value != "value" {
+		t.Fatalf("unexpected response: %+v", res)
+	}
+}
+
+func TestServerLoopbackEndpoints(t *testing.T) {
+	t.Parallel()
+
+	s := NewServer(logger.NewLogger(), map[string]interface{}{"key": "value"})
+
+	if err := s.Serve(); err != nil {
+		t.Fatal(err)
+	}
+
+	defer s.Stop()
+
+	// Without temp dir unix sockets are not available and endpoints fall back to loopback interface.
+	_ = os.RemoveAll(s.tempDir)
+	s.tempDir = ""
+
+	if _, err := s.Endpoint("legacy", false); err == nil {
+		t.Fatal("expected plugin without token support to be refused on loopback interface")
+	}
+
+	cli := dialEndpoint(t, s, "secure", true)
+
+	if _, err := cli.HostGetSecret(context.Background(), &apiv1.HostGetSecretRequest{Key: "key"}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected unauthenticated error without token, got: %v", err)
+	}
+
+	res, err := cli.HostGetSecret(metadata.AppendToOutgoingContext(context.Background(), TokenMetadataKey, s.Token()), &apiv1.HostGetSecretRequest{Key: "key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !res.Specified || res.Value != "value" {
+		t.Fatalf("unexpected response: %+v", res)
+	}
+}